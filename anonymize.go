@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+const (
+	anonymizeDomain = "go.gearno.de/crypto/uuid anonymize"
+)
+
+// Anonymize returns a stable pseudonymous custom (version 8) UUID
+// derived from uuid with SHA-256. The result cannot be reversed and
+// does not embed the timestamp of uuid, making it suitable for
+// analytics exports. Since anyone can compute it, it does not prevent
+// linking a known identifier to its pseudonym; use AnonymizeKeyed for
+// that.
+func Anonymize(uuid UUID) UUID {
+	h := sha256.New()
+	h.Write([]byte(anonymizeDomain))
+	h.Write(uuid[:])
+
+	return anonymized(h.Sum(nil))
+}
+
+// AnonymizeKeyed is like Anonymize but derives the pseudonym with
+// HMAC-SHA-256 under key, so that pseudonyms cannot be computed, nor
+// linked to known identifiers, without the key.
+func AnonymizeKeyed(key []byte, uuid UUID) UUID {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(anonymizeDomain))
+	h.Write(uuid[:])
+
+	return anonymized(h.Sum(nil))
+}
+
+func anonymized(sum []byte) UUID {
+	var uuid UUID
+
+	copy(uuid[:], sum)
+	uuid.SetVersion(V8)
+	uuid.SetVariant(VariantRFC9562)
+
+	return uuid
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	// UUIDArray is a list of UUIDs stored in a PostgreSQL uuid[]
+	// column. It implements sql.Scanner and driver.Valuer, so that
+	// it can be passed as a query argument, for example to
+	// "WHERE id = ANY($1)".
+	UUIDArray []UUID
+)
+
+const (
+	pgUUIDOID = 2950
+)
+
+var (
+	ErrInvalidArray = fmt.Errorf("%w: invalid array", ErrInvalidFormat)
+)
+
+// Scan implements sql.Scanner. It accepts one-dimensional arrays in
+// the PostgreSQL text and binary formats, and NULL which sets a to
+// nil. Arrays containing NULL elements are rejected.
+func (a *UUIDArray) Scan(src any) error {
+	var (
+		uuids UUIDArray
+		err   error
+	)
+
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+
+	case string:
+		uuids, err = parseTextArray([]byte(src))
+
+	case []byte:
+		if len(src) > 0 && src[0] == '{' {
+			uuids, err = parseTextArray(src)
+		} else {
+			uuids, err = parseBinaryArray(src)
+		}
+
+	default:
+		return fmt.Errorf("cannot scan %T into uuid array", src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*a = uuids
+	return nil
+}
+
+func parseTextArray(b []byte) (UUIDArray, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) < 2 || b[0] != '{' || b[len(b)-1] != '}' {
+		return nil, ErrInvalidArray
+	}
+
+	b = bytes.TrimSpace(b[1 : len(b)-1])
+	if len(b) == 0 {
+		return UUIDArray{}, nil
+	}
+
+	uuids := make(UUIDArray, 0, (len(b)+1)/37)
+	for len(b) > 0 {
+		var element []byte
+
+		element, b, _ = bytes.Cut(b, []byte{','})
+		element = bytes.TrimSpace(element)
+
+		if n := len(element); n >= 2 && element[0] == '"' && element[n-1] == '"' {
+			element = element[1 : n-1]
+		}
+
+		uuid, err := parseText(element)
+		if err != nil {
+			return nil, ErrInvalidArray
+		}
+
+		uuids = append(uuids, uuid)
+	}
+
+	return uuids, nil
+}
+
+// parseBinaryArray decodes the binary format of PostgreSQL arrays: a
+// header made of the number of dimensions, a flag indicating the
+// presence of NULL elements and the element type, followed by the
+// length and lower bound of each dimension, then by the elements,
+// each prefixed by its length.
+func parseBinaryArray(b []byte) (UUIDArray, error) {
+	if len(b) < 12 {
+		return nil, ErrInvalidArray
+	}
+
+	ndim := binary.BigEndian.Uint32(b[0:4])
+	oid := binary.BigEndian.Uint32(b[8:12])
+
+	if ndim == 0 {
+		return UUIDArray{}, nil
+	}
+
+	if ndim != 1 || oid != pgUUIDOID || len(b) < 20 {
+		return nil, ErrInvalidArray
+	}
+
+	n := binary.BigEndian.Uint32(b[12:16])
+	b = b[20:]
+
+	if uint64(len(b)) != 20*uint64(n) {
+		return nil, ErrInvalidArray
+	}
+
+	uuids := make(UUIDArray, n)
+	for i := range uuids {
+		if binary.BigEndian.Uint32(b[0:4]) != 16 {
+			return nil, ErrInvalidArray
+		}
+
+		uuids[i] = UUID(b[4:20])
+		b = b[20:]
+	}
+
+	return uuids, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrIntegerOutOfRange = errors.New("integer out of range")
+)
+
+// BigInt returns uuid as an unsigned 128-bit big-endian integer.
+func (uuid UUID) BigInt() *big.Int {
+	return new(big.Int).SetBytes(uuid[:])
+}
+
+// FromBigInt returns the UUID whose unsigned 128-bit big-endian
+// integer value is x. It returns ErrIntegerOutOfRange if x is
+// negative or does not fit in 128 bits.
+func FromBigInt(x *big.Int) (UUID, error) {
+	var uuid UUID
+
+	if x.Sign() < 0 || x.BitLen() > 128 {
+		return Nil, ErrIntegerOutOfRange
+	}
+
+	x.FillBytes(uuid[:])
+
+	return uuid, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+type (
+	// BloomFilter is a probabilistic set of UUIDs answering whether
+	// a UUID was possibly added, or definitely not. The random bits
+	// of version 4 UUIDs are used directly as hash values; other
+	// versions, including version 7 whose rand_b may hold a shard ID,
+	// are hashed with Hash64. A BloomFilter is not safe for
+	// concurrent use.
+	BloomFilter struct {
+		bits []uint64
+		m    uint64
+		k    uint32
+	}
+)
+
+const (
+	// maxBloomHashes is the maximum number of hash functions of a
+	// BloomFilter, reached for false positive rates below 2^-64.
+	maxBloomHashes = 64
+)
+
+var (
+	ErrInvalidBloomFilter = fmt.Errorf("%w: invalid bloom filter", ErrInvalidFormat)
+)
+
+// NewBloomFilter returns a BloomFilter sized to hold n UUIDs with a
+// false positive rate of p. It panics if n is not positive or if p is
+// not between 0 and 1 exclusive.
+func NewBloomFilter(n int, p float64) *BloomFilter {
+	if n <= 0 || p <= 0 || p >= 1 {
+		panic("uuid: invalid bloom filter parameters")
+	}
+
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Min(maxBloomHashes, math.Max(1, math.Round(m/float64(n)*math.Ln2)))
+
+	return newBloomFilter(uint64(m), uint32(k))
+}
+
+func newBloomFilter(m uint64, k uint32) *BloomFilter {
+	return &BloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add adds uuid to f.
+func (f *BloomFilter) Add(uuid UUID) {
+	h1, h2 := bloomHashes(uuid)
+
+	for i := uint32(0); i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MaybeContains reports whether uuid may have been added to f. It
+// never returns false for an added UUID.
+func (f *BloomFilter) MaybeContains(uuid UUID) bool {
+	h1, h2 := bloomHashes(uuid)
+
+	for i := uint32(0); i < f.k; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is
+// the number of bits as a big-endian uint64, the number of hash
+// functions as a big-endian uint32, then the bits as big-endian
+// uint64 words.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 12+8*len(f.bits))
+	buf = binary.BigEndian.AppendUint64(buf, f.m)
+	buf = binary.BigEndian.AppendUint32(buf, f.k)
+
+	for _, word := range f.bits {
+		buf = binary.BigEndian.AppendUint64(buf, word)
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return ErrInvalidBloomFilter
+	}
+
+	m := binary.BigEndian.Uint64(data[0:8])
+	k := binary.BigEndian.Uint32(data[8:12])
+	data = data[12:]
+
+	// Check m against the length of data first, so that computing
+	// the number of words cannot overflow.
+	if m == 0 || k == 0 || k > maxBloomHashes || m > uint64(len(data))*8 || uint64(len(data)) != (m+63)/64*8 {
+		return ErrInvalidBloomFilter
+	}
+
+	g := newBloomFilter(m, k)
+	for i := range g.bits {
+		g.bits[i] = binary.BigEndian.Uint64(data[8*i:])
+	}
+
+	*f = *g
+	return nil
+}
+
+// bloomHashes returns the two hash values combined to derive the bit
+// positions of uuid, as described by Kirsch and Mitzenmacher.
+func bloomHashes(uuid UUID) (uint64, uint64) {
+	var h uint64
+
+	if uuid.Version() == V4 {
+		h = binary.BigEndian.Uint64(uuid[8:]) & (1<<62 - 1)
+	} else {
+		h = uuid.Hash64()
+	}
+
+	return h, bits.RotateLeft64(h, 32) | 1
+}
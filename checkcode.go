@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	checkCodeDomain = "go.gearno.de/crypto/uuid check code"
+
+	// MaxCheckCodeLen is the maximum number of digits of a check
+	// code.
+	MaxCheckCodeLen = 12
+)
+
+// CheckCode returns an n digits decimal code derived from uuid with
+// SHA-256, intended for human confirmation flows ("does the code on
+// your screen match?"). It is not a secret and does not authenticate
+// anything on its own. It panics if n is not between 1 and
+// MaxCheckCodeLen.
+func (uuid UUID) CheckCode(n int) string {
+	if n < 1 || n > MaxCheckCodeLen {
+		panic(fmt.Sprintf("uuid: invalid check code length %d", n))
+	}
+
+	h := sha256.New()
+	h.Write([]byte(checkCodeDomain))
+	h.Write(uuid[:])
+	sum := h.Sum(nil)
+
+	var modulus uint64 = 1
+	for i := 0; i < n; i++ {
+		modulus *= 10
+	}
+
+	return fmt.Sprintf("%0*d", n, binary.BigEndian.Uint64(sum)%modulus)
+}
+
+// VerifyCheckCode reports whether code is the check code of uuid, in
+// constant time. The length of the code is taken from code itself.
+func (uuid UUID) VerifyCheckCode(code string) bool {
+	if len(code) < 1 || len(code) > MaxCheckCodeLen {
+		return false
+	}
+
+	expected := uuid.CheckCode(len(code))
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1
+}
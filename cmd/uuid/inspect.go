@@ -0,0 +1,74 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	// info describes a UUID, as printed by the inspect command and
+	// returned by the HTTP server.
+	info struct {
+		Input   string     `json:"input"`
+		UUID    string     `json:"uuid,omitempty"`
+		Version int        `json:"version,omitempty"`
+		Variant string     `json:"variant,omitempty"`
+		Time    *time.Time `json:"time,omitempty"`
+		Error   string     `json:"error,omitempty"`
+	}
+)
+
+func inspect(s string) info {
+	i := info{Input: s}
+
+	u, err := uuid.ParseLenient(s)
+	if err != nil {
+		i.Error = err.Error()
+		return i
+	}
+
+	i.UUID = u.String()
+	i.Version = int(u.Version())
+	i.Variant = u.Variant().String()
+
+	if t := u.Timestamp(); !t.IsZero() {
+		t = t.UTC()
+		i.Time = &t
+	}
+
+	return i
+}
+
+// String returns i as a single line of text.
+func (i info) String() string {
+	if i.Error != "" {
+		return fmt.Sprintf("%s: %s", i.Input, i.Error)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s version=%d variant=%q", i.UUID, i.Version, i.Variant)
+	if i.Time != nil {
+		fmt.Fprintf(&b, " time=%s", i.Time.Format(time.RFC3339Nano))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Command uuid generates and inspects UUIDs.
+//
+// Usage:
+//
+//	uuid [gen] [-v version] [-n count]
+//	uuid inspect uuid...
+//	uuid serve [-addr address]
+//
+// The gen command, the default, prints count new UUIDs of the given
+// version (4 or 7). The inspect command prints the version, variant
+// and timestamp of every argument. The serve command runs an HTTP
+// server minting and inspecting UUIDs, for programs which cannot use
+// this package directly:
+//
+//	GET  /v4?n=count  mint count version 4 UUIDs
+//	GET  /v7?n=count  mint count version 7 UUIDs
+//	POST /inspect     inspect the UUIDs of the body, one per line
+//
+// UUIDs are returned one per line, or as a JSON array if the request
+// accepts application/json.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+const (
+	// maxBatch is the maximum number of UUIDs minted at once by
+	// the HTTP server.
+	maxBatch = 1000
+)
+
+func main() {
+	args := os.Args[1:]
+
+	cmd := "gen"
+	if len(args) > 0 && (args[0] == "gen" || args[0] == "inspect" || args[0] == "serve") {
+		cmd, args = args[0], args[1:]
+	}
+
+	var err error
+	switch cmd {
+	case "gen":
+		err = runGen(args)
+	case "inspect":
+		err = runInspect(args)
+	case "serve":
+		err = runServe(args)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "uuid: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runGen(args []string) error {
+	var (
+		fs      = flag.NewFlagSet("gen", flag.ExitOnError)
+		version = fs.Int("v", 4, "UUID `version` (4 or 7)")
+		n       = fs.Int("n", 1, "number of UUIDs")
+	)
+	fs.Parse(args)
+
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	newFn, err := generator(uuid.Version(*version))
+	if err != nil {
+		return err
+	}
+
+	for range *n {
+		u, err := newFn()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(u)
+	}
+
+	return nil
+}
+
+func runInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	fs.Parse(args)
+
+	var failed bool
+	for _, arg := range fs.Args() {
+		info := inspect(arg)
+		if info.Error != "" {
+			failed = true
+		}
+
+		fmt.Println(info)
+	}
+
+	if failed {
+		return errors.New("invalid UUID")
+	}
+
+	return nil
+}
+
+func generator(v uuid.Version) (func() (uuid.UUID, error), error) {
+	switch v {
+	case uuid.V4:
+		return uuid.NewV4, nil
+	case uuid.V7:
+		return uuid.NewV7, nil
+	default:
+		return nil, fmt.Errorf("unsupported version %d", v)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+func runServe(args []string) error {
+	var (
+		fs   = flag.NewFlagSet("serve", flag.ExitOnError)
+		addr = fs.String("addr", "localhost:8080", "listen `address`")
+	)
+	fs.Parse(args)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v4", mintHandler(uuid.V4))
+	mux.HandleFunc("GET /v7", mintHandler(uuid.V7))
+	mux.HandleFunc("POST /inspect", inspectHandler)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("listening on %s", *addr)
+
+	return server.ListenAndServe()
+}
+
+func mintHandler(v uuid.Version) http.HandlerFunc {
+	newFn, _ := generator(v)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := 1
+		if s := r.URL.Query().Get("n"); s != "" {
+			var err error
+			n, err = strconv.Atoi(s)
+			if err != nil || n < 1 || n > maxBatch {
+				http.Error(w, fmt.Sprintf("n must be between 1 and %d", maxBatch), http.StatusBadRequest)
+				return
+			}
+		}
+
+		uuids := make(uuid.UUIDs, n)
+		for i := range uuids {
+			var err error
+			uuids[i], err = newFn()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if acceptsJSON(r) {
+			writeJSON(w, uuids)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write(append(uuids.FormatMany(nil, '\n'), '\n'))
+	}
+}
+
+func inspectHandler(w http.ResponseWriter, r *http.Request) {
+	var infos []info
+
+	scanner := bufio.NewScanner(http.MaxBytesReader(w, r.Body, 64*maxBatch))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if len(infos) == maxBatch {
+			http.Error(w, fmt.Sprintf("at most %d UUIDs can be inspected", maxBatch), http.StatusBadRequest)
+			return
+		}
+
+		infos = append(infos, inspect(line))
+	}
+
+	if err := scanner.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if acceptsJSON(r) {
+		writeJSON(w, infos)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, i := range infos {
+		fmt.Fprintln(w, i)
+	}
+}
+
+func acceptsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Command uuidgen-go generates a Go file declaring UUID variables from
+// a list of named UUIDs, so that well-known identifiers are validated
+// once at generation time instead of being parsed at run time.
+//
+// Usage:
+//
+//	uuidgen-go [-pkg name] [-o file] [input]
+//
+// Every line of the input, or of the standard input if none is given,
+// holds a Go identifier and a UUID separated by whitespace, in any
+// textual form accepted by uuid.ParseAny; the 16 bytes binary form is
+// rejected, as a mistyped value could be taken for one. Empty lines
+// and lines starting with "#" are ignored, but at least one UUID must
+// be declared. The output is written to the standard output unless -o
+// is set, in the package named by -pkg, which defaults to the package
+// of the go:generate directive:
+//
+//	//go:generate go run go.gearno.de/crypto/uuid/cmd/uuidgen-go -o ids.go ids.txt
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	entry struct {
+		name string
+		uuid uuid.UUID
+	}
+)
+
+func main() {
+	var (
+		pkg    = flag.String("pkg", os.Getenv("GOPACKAGE"), "package `name` of the generated file")
+		output = flag.String("o", "", "output `file`")
+	)
+	flag.Parse()
+
+	if err := run(*pkg, *output, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "uuidgen-go: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, output, input string) error {
+	if pkg == "" {
+		return errors.New("missing package name")
+	}
+
+	r := io.Reader(os.Stdin)
+	if input != "" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	} else {
+		input = "<stdin>"
+	}
+
+	entries, err := readEntries(r, input)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg, entries)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
+
+func readEntries(r io.Reader, filename string) ([]entry, error) {
+	var (
+		entries []entry
+		names   = make(map[string]int)
+		uuids   = make(map[uuid.UUID]int)
+		scanner = bufio.NewScanner(r)
+	)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a name and a uuid", filename, line)
+		}
+
+		name := fields[0]
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("%s:%d: invalid identifier %q", filename, line, name)
+		}
+		if previous, ok := names[name]; ok {
+			return nil, fmt.Errorf("%s:%d: %s already declared at line %d", filename, line, name, previous)
+		}
+
+		id, err := parseUUID(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid uuid %q: %w", filename, line, fields[1], err)
+		}
+		if previous, ok := uuids[id]; ok {
+			return nil, fmt.Errorf("%s:%d: %s already declared at line %d", filename, line, id, previous)
+		}
+
+		names[name] = line
+		uuids[id] = line
+		entries = append(entries, entry{name: name, uuid: id})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no uuid declared", filename)
+	}
+
+	return entries, nil
+}
+
+// parseUUID parses the textual forms accepted by uuid.ParseAny,
+// rejecting 16 bytes tokens which it would read as binary UUIDs.
+func parseUUID(s string) (uuid.UUID, error) {
+	if len(s) == 16 {
+		return uuid.Nil, uuid.ErrInvalidLength
+	}
+
+	return uuid.ParseAny([]byte(s))
+}
+
+func generate(pkg string, entries []entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by uuidgen-go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import \"go.gearno.de/crypto/uuid\"\n\n")
+	fmt.Fprintf(&buf, "var (\n")
+
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\t// %s is %s.\n", e.name, e.uuid.Format("D"))
+		fmt.Fprintf(&buf, "\t%s = uuid.UUID{", e.name)
+		for i, b := range e.uuid {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%#02x", b)
+		}
+		buf.WriteString("}\n")
+	}
+
+	fmt.Fprintf(&buf, ")\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"errors"
+	"sync"
+)
+
+type (
+	// CollisionDetector is a Generator wrapping another one and
+	// remembering the last UUIDs it generated, so that duplicates
+	// caused by a misconfigured entropy source are noticed. It is
+	// intended for test environments.
+	CollisionDetector struct {
+		gen         Generator
+		onCollision func(UUID)
+
+		mu     sync.Mutex
+		window []UUID
+		next   int
+		seen   map[UUID]struct{}
+	}
+)
+
+var (
+	ErrCollision = errors.New("collision detected")
+
+	_ Generator = (*CollisionDetector)(nil)
+)
+
+// NewCollisionDetector returns a CollisionDetector wrapping gen and
+// remembering the last size UUIDs generated. If onCollision is nil,
+// generating a duplicate UUID returns ErrCollision; otherwise
+// onCollision is called with the duplicate, which is returned
+// normally.
+func NewCollisionDetector(gen Generator, size int, onCollision func(UUID)) *CollisionDetector {
+	if size <= 0 {
+		panic("uuid: invalid collision detector size")
+	}
+
+	return &CollisionDetector{
+		gen:         gen,
+		onCollision: onCollision,
+		window:      make([]UUID, 0, size),
+		seen:        make(map[UUID]struct{}, size),
+	}
+}
+
+// NewV1 returns a Gregorian time-based (version 1) UUID from the
+// wrapped generator.
+func (d *CollisionDetector) NewV1() (UUID, error) {
+	return d.check(d.gen.NewV1())
+}
+
+// NewV2 returns a DCE Security (version 2) UUID from the wrapped
+// generator.
+func (d *CollisionDetector) NewV2(domain Domain, id uint32) (UUID, error) {
+	return d.check(d.gen.NewV2(domain, id))
+}
+
+// NewV4 returns a random (version 4) UUID from the wrapped generator.
+func (d *CollisionDetector) NewV4() (UUID, error) {
+	return d.check(d.gen.NewV4())
+}
+
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID
+// from the wrapped generator.
+func (d *CollisionDetector) NewV6() (UUID, error) {
+	return d.check(d.gen.NewV6())
+}
+
+// NewV7 returns a time-ordered (version 7) UUID from the wrapped
+// generator.
+func (d *CollisionDetector) NewV7() (UUID, error) {
+	return d.check(d.gen.NewV7())
+}
+
+func (d *CollisionDetector) check(uuid UUID, err error) (UUID, error) {
+	if err != nil {
+		return Nil, err
+	}
+
+	d.mu.Lock()
+	_, found := d.seen[uuid]
+	if !found {
+		d.remember(uuid)
+	}
+	d.mu.Unlock()
+
+	if found {
+		if d.onCollision == nil {
+			return Nil, ErrCollision
+		}
+
+		d.onCollision(uuid)
+	}
+
+	return uuid, nil
+}
+
+func (d *CollisionDetector) remember(uuid UUID) {
+	if len(d.window) < cap(d.window) {
+		d.window = append(d.window, uuid)
+	} else {
+		delete(d.seen, d.window[d.next])
+		d.window[d.next] = uuid
+		d.next = (d.next + 1) % len(d.window)
+	}
+
+	d.seen[uuid] = struct{}{}
+}
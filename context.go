@@ -0,0 +1,37 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"context"
+)
+
+type (
+	contextKey struct{}
+)
+
+// NewContext returns a copy of ctx carrying uuid, typically a request
+// or correlation identifier.
+func NewContext(ctx context.Context, uuid UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, uuid)
+}
+
+// FromContext returns the UUID carried by ctx, if any.
+func FromContext(ctx context.Context) (UUID, bool) {
+	uuid, ok := ctx.Value(contextKey{}).(UUID)
+	return uuid, ok
+}
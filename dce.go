@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type (
+	// Domain is the local domain of a DCE Security (version 2)
+	// UUID, defining the meaning of its local identifier.
+	Domain byte
+)
+
+const (
+	// DomainPerson is the domain of POSIX UIDs.
+	DomainPerson Domain = iota
+
+	// DomainGroup is the domain of POSIX GIDs.
+	DomainGroup
+
+	// DomainOrg is the organization domain.
+	DomainOrg
+)
+
+// String implements fmt.Stringer.
+func (d Domain) String() string {
+	switch d {
+	case DomainPerson:
+		return "Person"
+	case DomainGroup:
+		return "Group"
+	case DomainOrg:
+		return "Org"
+	default:
+		return fmt.Sprintf("Domain(%d)", byte(d))
+	}
+}
+
+// Domain returns the local domain of a DCE Security (version 2) UUID.
+// The result is meaningless for other versions.
+func (uuid UUID) Domain() Domain {
+	return Domain(uuid[9])
+}
+
+// DomainID returns the local identifier of a DCE Security (version 2)
+// UUID, such as the POSIX UID or GID of the Person and Group domains.
+// The result is meaningless for other versions.
+func (uuid UUID) DomainID() uint32 {
+	return binary.BigEndian.Uint32(uuid[0:4])
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+//go:build !uuid_nocryptorand
+
+package uuid
+
+// Since Go 1.27, database/sql/driver depends on crypto/rand, so that
+// driver.Valuer is not implemented when the package is built with the
+// uuid_nocryptorand tag.
+
+import (
+	"database/sql/driver"
+)
+
+// Value implements driver.Valuer, returning the canonical text form
+// of uuid.
+func (uuid UUID) Value() (driver.Value, error) {
+	return string(uuid.appendCanonical(make([]byte, 0, 36))), nil
+}
+
+// Value implements driver.Valuer like UUID.Value.
+func (uuid StrictUUID) Value() (driver.Value, error) {
+	return UUID(uuid).Value()
+}
+
+// Value implements driver.Valuer like UUID.Value.
+func (uuid LenientUUID) Value() (driver.Value, error) {
+	return UUID(uuid).Value()
+}
+
+// Value implements driver.Valuer, returning the text representation
+// of the array. A nil array is stored as NULL.
+func (a UUIDArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	buf := make([]byte, 0, 2+37*len(a))
+	buf = append(buf, '{')
+	for i, uuid := range a {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		buf = uuid.appendCanonical(buf)
+	}
+	buf = append(buf, '}')
+
+	return string(buf), nil
+}
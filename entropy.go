@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// minEntropyBackoff is the minimum delay between two reads of
+	// a failing entropy source, which avoids spinning.
+	minEntropyBackoff = time.Millisecond
+)
+
+type (
+	// EntropyRetryPolicy defines how a Gen retries failed reads of
+	// its entropy source. Only failed reads are retried: a read which
+	// blocks is neither detected nor interrupted, so that sources
+	// which may stall must bound their reads themselves.
+	EntropyRetryPolicy struct {
+		// Attempts is the maximum number of reads attempted for
+		// a single UUID. Values lower than 1 are treated as 1.
+		Attempts int
+
+		// Backoff is the delay before the first retry, at least
+		// one millisecond. It is doubled after every retry.
+		Backoff time.Duration
+
+		// MaxBackoff caps the delay between two retries if
+		// positive.
+		MaxBackoff time.Duration
+	}
+
+	// EntropyError is returned by a Gen when its entropy source
+	// could not be read. It wraps the error of the last attempt.
+	EntropyError struct {
+		Attempts int
+		Err      error
+	}
+
+	entropyReader struct{}
+)
+
+var (
+	entropySource atomic.Pointer[io.Reader]
+
+	// entropy is the package-level entropy source, used by
+	// generators which were not given one with WithRandReader and by
+	// package-level functions.
+	entropy io.Reader = entropyReader{}
+)
+
+func init() {
+	SetEntropySource(defaultEntropySource())
+}
+
+// SetEntropySource replaces the package-level entropy source, which
+// is crypto/rand by default, and returns the previous one. It allows
+// firmware to supply a hardware TRNG on embedded targets where
+// crypto/rand is unavailable or slow. It affects every generator not
+// configured with WithRandReader, including the default generator,
+// and is read concurrently: r must be safe for concurrent use.
+func SetEntropySource(r io.Reader) io.Reader {
+	if r == nil {
+		panic("uuid: nil entropy source")
+	}
+
+	previous := entropySource.Swap(&r)
+	if previous == nil {
+		return nil
+	}
+
+	return *previous
+}
+
+// Read implements io.Reader.
+func (entropyReader) Read(p []byte) (int, error) {
+	return (*entropySource.Load()).Read(p)
+}
+
+// Error implements error.
+func (e *EntropyError) Error() string {
+	return fmt.Sprintf("cannot read entropy source after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *EntropyError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+//go:build uuid_nocryptorand
+
+package uuid
+
+import (
+	"errors"
+	"io"
+)
+
+type (
+	noEntropyReader struct{}
+)
+
+var (
+	ErrNoEntropySource = errors.New("no entropy source")
+)
+
+// When built with the uuid_nocryptorand tag, for targets where
+// crypto/rand is not available, the package does not depend on
+// crypto/rand and SetEntropySource must be called before generating
+// random UUIDs.
+func defaultEntropySource() io.Reader {
+	return noEntropyReader{}
+}
+
+func fipsEntropySource() (io.Reader, bool) {
+	return nil, false
+}
+
+func (noEntropyReader) Read([]byte) (int, error) {
+	return 0, ErrNoEntropySource
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+)
+
+type (
+	// Match is a UUID found in a text, along with the offset of its
+	// first byte.
+	Match struct {
+		UUID   UUID
+		Offset int
+	}
+)
+
+// FindAll returns every UUID in canonical form found in b, in order
+// of appearance. A UUID is only recognized if it is not immediately
+// preceded or followed by a hexadecimal digit or a dash, so that
+// longer hexadecimal strings are not reported.
+func FindAll(b []byte) []Match {
+	var matches []Match
+
+	Matches(b)(func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+
+	return matches
+}
+
+// Matches returns an iterator over the UUIDs found in b, as reported
+// by FindAll. It stops scanning as soon as yield returns false, and
+// can be used with range-over-func loops.
+func Matches(b []byte) func(yield func(Match) bool) {
+	return func(yield func(Match) bool) {
+		for i := 0; i+36 <= len(b); {
+			j := bytes.IndexByte(b[i+8:], '-')
+			if j < 0 {
+				return
+			}
+
+			start := i + j
+			if start+36 > len(b) {
+				return
+			}
+
+			uuid, err := ParseBytes(b[start : start+36])
+			if err != nil || !isFindBoundary(b, start-1) ||
+				!isFindBoundary(b, start+36) {
+				i = start + 1
+				continue
+			}
+
+			if !yield(Match{UUID: uuid, Offset: start}) {
+				return
+			}
+
+			i = start + 36
+		}
+	}
+}
+
+func isFindBoundary(b []byte, i int) bool {
+	if i < 0 || i >= len(b) {
+		return true
+	}
+
+	_, isHex := hexValue(b[i])
+
+	return !isHex && b[i] != '-'
+}
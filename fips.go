@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"errors"
+)
+
+type (
+	// FIPSAttestation describes the entropy source of a Gen for
+	// compliance reporting.
+	FIPSAttestation struct {
+		// Approved reports whether entropy is read from the
+		// DRBG of the Go Cryptographic Module running in FIPS
+		// 140-3 mode.
+		Approved bool
+
+		// ModuleVersion is the version of the Go Cryptographic
+		// Module, or the empty string if it is unknown.
+		ModuleVersion string
+	}
+)
+
+var (
+	ErrFIPSDisabled = errors.New("fips 140-3 mode disabled")
+)
+
+// NewFIPSGen returns a Gen reading its entropy from crypto/rand, which
+// uses the FIPS 140-3 approved DRBG of the Go Cryptographic Module. It
+// returns ErrFIPSDisabled unless the program runs in FIPS 140-3 mode,
+// as enabled with GODEBUG=fips140=on or GOFIPS140, or if the package
+// is built with the uuid_nocryptorand tag. Options changing the
+// entropy source are ignored.
+func NewFIPSGen(options ...GenOption) (*Gen, error) {
+	r, ok := fipsEntropySource()
+	if !ok || !fips140Enabled() {
+		return nil, ErrFIPSDisabled
+	}
+
+	g := NewGen(append(options[:len(options):len(options)], withConcurrentRandReader(r))...)
+	g.fips = true
+
+	return g, nil
+}
+
+// FIPSAttestation reports whether g reads its entropy from a FIPS 140-3
+// approved source.
+func (g *Gen) FIPSAttestation() FIPSAttestation {
+	return FIPSAttestation{
+		Approved:      g.fips && fips140Enabled(),
+		ModuleVersion: fips140Version(),
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+type (
+	// TextFormat describes the textual form produced by
+	// UUID.MarshalText and UUID.String.
+	TextFormat struct {
+		// Layout is one of the "D", "N", "B" and "P" layouts of
+		// UUID.Format.
+		Layout string
+
+		// Upper selects upper case hexadecimal digits.
+		Upper bool
+	}
+
+	hexStructParser struct {
+		s   string
+		pos int
+	}
+)
+
+var (
+	textFormat atomic.Pointer[TextFormat]
+)
+
+// SetTextFormat changes the textual form produced by UUID.MarshalText
+// and UUID.String for the whole program, and returns the previous
+// one. This allows a service to comply with a formatting contract
+// without wrapping every call site. The zero TextFormat restores the
+// canonical form. It panics if f.Layout is not valid.
+func SetTextFormat(f TextFormat) TextFormat {
+	switch f.Layout {
+	case "", "D", "d", "N", "n", "B", "b", "P", "p":
+	default:
+		panic("uuid: invalid text format layout " + f.Layout)
+	}
+
+	var previous *TextFormat
+	if f == (TextFormat{}) {
+		previous = textFormat.Swap(nil)
+	} else {
+		previous = textFormat.Swap(&f)
+	}
+
+	if previous == nil {
+		return TextFormat{}
+	}
+
+	return *previous
+}
+
+// Format returns the textual representation of uuid using one of the
+// .NET Guid.ToString format specifiers:
+//
+//	"D": 00000000-0000-0000-0000-000000000000
+//	"N": 00000000000000000000000000000000
+//	"B": {00000000-0000-0000-0000-000000000000}
+//	"P": (00000000-0000-0000-0000-000000000000)
+//	"X": {0x00000000,0x0000,0x0000,{0x00,0x00,0x00,0x00,0x00,0x00,0x00,0x00}}
+//
+// Specifiers are case-insensitive, and an empty layout is equivalent
+// to "D". Format ignores the configured TextFormat. It panics if
+// layout is not one of the above.
+func (uuid UUID) Format(layout string) string {
+	return string(uuid.appendFormat(make([]byte, 0, 68), layout))
+}
+
+func (uuid UUID) appendFormat(buf []byte, layout string) []byte {
+	switch layout {
+	case "", "D", "d":
+		return uuid.appendCanonical(buf)
+
+	case "N", "n":
+		return hex.AppendEncode(buf, uuid[:])
+
+	case "B", "b":
+		buf = append(buf, '{')
+		buf = uuid.appendCanonical(buf)
+		return append(buf, '}')
+
+	case "P", "p":
+		buf = append(buf, '(')
+		buf = uuid.appendCanonical(buf)
+		return append(buf, ')')
+
+	case "X", "x":
+		return uuid.appendHexStruct(buf)
+
+	default:
+		panic("uuid: invalid format layout " + layout)
+	}
+}
+
+func (uuid UUID) appendCanonical(buf []byte) []byte {
+	n := len(buf)
+	buf = append(buf, make([]byte, 36)...)
+	uuid.encodeCanonical(buf[n:])
+
+	return buf
+}
+
+func (uuid UUID) appendHexStruct(buf []byte) []byte {
+	buf = append(buf, "{0x"...)
+	buf = hex.AppendEncode(buf, uuid[0:4])
+	buf = append(buf, ",0x"...)
+	buf = hex.AppendEncode(buf, uuid[4:6])
+	buf = append(buf, ",0x"...)
+	buf = hex.AppendEncode(buf, uuid[6:8])
+	buf = append(buf, ",{"...)
+
+	for i := 8; i < 16; i++ {
+		if i > 8 {
+			buf = append(buf, ',')
+		}
+
+		buf = append(buf, "0x"...)
+		buf = hex.AppendEncode(buf, uuid[i:i+1])
+	}
+
+	return append(buf, "}}"...)
+}
+
+// appendText appends the textual form of uuid produced by
+// MarshalText to buf.
+func (uuid UUID) appendText(buf []byte) []byte {
+	if f := textFormat.Load(); f != nil {
+		return f.append(buf, uuid)
+	}
+
+	return uuid.appendCanonical(buf)
+}
+
+func (f *TextFormat) append(buf []byte, uuid UUID) []byte {
+	n := len(buf)
+	buf = uuid.appendFormat(buf, f.Layout)
+
+	if f.Upper {
+		for i := n; i < len(buf); i++ {
+			if buf[i] >= 'a' && buf[i] <= 'f' {
+				buf[i] -= 'a' - 'A'
+			}
+		}
+	}
+
+	return buf
+}
+
+// parseText parses any of the "D", "N", "B" and "P" layouts.
+func parseText(b []byte) (UUID, error) {
+	switch len(b) {
+	case 36:
+		return ParseBytes(b)
+
+	case 32:
+		var uuid UUID
+		if _, err := hex.Decode(uuid[:], b); err != nil {
+			return Nil, ErrInvalidCharacter
+		}
+
+		return uuid, nil
+
+	case 38:
+		if (b[0] == '{' && b[37] == '}') || (b[0] == '(' && b[37] == ')') {
+			return ParseBytes(b[1:37])
+		}
+
+		return Nil, ErrInvalidSeparator
+	}
+
+	return Nil, ErrInvalidLength
+}
+
+// ParseAny decodes b from any representation it can recognize by its
+// length: the 16 bytes binary form, the "D", "N", "B" and "P" layouts
+// of UUID.Format, and the "urn:uuid:" prefixed canonical form of RFC
+// 9562. It is meant for inputs whose representation is not known in
+// advance.
+func ParseAny(b []byte) (UUID, error) {
+	switch len(b) {
+	case 16:
+		return FromBytes(b)
+
+	case 45:
+		if !bytes.EqualFold(b[:9], []byte("urn:uuid:")) {
+			return Nil, ErrInvalidFormat
+		}
+
+		return ParseBytes(b[9:])
+	}
+
+	return parseText(b)
+}
+
+// ParseHexStruct decodes s from the "X" layout of UUID.Format, as
+// found in Windows registry exports and C headers:
+//
+//	{0x6b29fc40,0xca47,0x1067,{0xb3,0x1d,0x00,0xdd,0x01,0x06,0x62,0xda}}
+//
+// Whitespace is allowed between elements, hexadecimal digits are
+// case-insensitive and leading zeros may be omitted.
+func ParseHexStruct(s string) (UUID, error) {
+	var (
+		uuid UUID
+		p    = hexStructParser{s: s}
+	)
+
+	ok := p.expect('{') &&
+		p.field(uuid[0:4]) && p.expect(',') &&
+		p.field(uuid[4:6]) && p.expect(',') &&
+		p.field(uuid[6:8]) && p.expect(',') &&
+		p.expect('{')
+
+	for i := 8; ok && i < 16; i++ {
+		if i > 8 {
+			ok = p.expect(',')
+		}
+
+		ok = ok && p.field(uuid[i:i+1])
+	}
+
+	ok = ok && p.expect('}') && p.expect('}')
+
+	p.skipSpaces()
+	if !ok || p.pos != len(p.s) {
+		return Nil, ErrInvalidFormat
+	}
+
+	return uuid, nil
+}
+
+func (p *hexStructParser) skipSpaces() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *hexStructParser) expect(c byte) bool {
+	p.skipSpaces()
+
+	if p.pos >= len(p.s) || p.s[p.pos] != c {
+		return false
+	}
+
+	p.pos++
+	return true
+}
+
+// field parses a 0x-prefixed hexadecimal number of at most len(dst)
+// bytes and stores it in dst in big-endian order.
+func (p *hexStructParser) field(dst []byte) bool {
+	p.skipSpaces()
+
+	if len(p.s)-p.pos < 3 || p.s[p.pos] != '0' ||
+		(p.s[p.pos+1] != 'x' && p.s[p.pos+1] != 'X') {
+		return false
+	}
+	p.pos += 2
+
+	var (
+		value  uint64
+		digits int
+	)
+
+	for ; p.pos < len(p.s); p.pos++ {
+		v, ok := hexValue(p.s[p.pos])
+		if !ok {
+			break
+		}
+
+		value = value<<4 | uint64(v)
+		digits++
+	}
+
+	if digits == 0 || digits > 2*len(dst) {
+		return false
+	}
+
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = byte(value)
+		value >>= 8
+	}
+
+	return true
+}
@@ -0,0 +1,680 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Generator is the interface implemented by UUID generators.
+	Generator interface {
+		NewV1() (UUID, error)
+		NewV2(domain Domain, id uint32) (UUID, error)
+		NewV4() (UUID, error)
+		NewV6() (UUID, error)
+		NewV7() (UUID, error)
+	}
+
+	// Gen generates UUIDs. Version 7 UUIDs produced by the same Gen
+	// are strictly increasing: the 12 bits of rand_a hold a counter
+	// incremented for every UUID generated within the same
+	// millisecond, as described in RFC 9562 section 6.2 (method 1).
+	//
+	// Version 1, 2 and 6 UUIDs share a node identifier and a clock
+	// sequence, incremented whenever the clock does not move forward
+	// between two UUIDs.
+	//
+	// A Gen is safe for concurrent use by multiple goroutines.
+	// Version 4 UUIDs are generated without taking its lock, so
+	// that they do not contend with time-based UUIDs.
+	Gen struct {
+		mu sync.Mutex
+
+		rand           io.Reader
+		randConcurrent bool
+		now            func() time.Time
+		rollbackPolicy ClockRollbackPolicy
+		shardBits      int
+		shardID        uint64
+		descending     bool
+		epoch          int64
+		jitter         int64
+		granularity    int64
+		variant        Variant
+		fips           bool
+
+		observer      Observer
+		entropyPolicy EntropyRetryPolicy
+
+		lastClock     int64
+		lastTimestamp uint64
+		counter       uint16
+
+		store       StateStore
+		stateLoaded bool
+		saved       GenState
+
+		gregorianInit bool
+		nodeSet       bool
+		node          [6]byte
+		clockSeq      uint16
+		lastGregorian uint64
+	}
+
+	// GenOption configures a Gen.
+	GenOption func(*Gen)
+
+	// ClockRollbackPolicy defines how a Gen behaves when the wall
+	// clock goes backwards between two version 7 UUIDs.
+	ClockRollbackPolicy int
+
+	// ClockRollbackError is returned by a Gen configured with the
+	// ClockRollbackFail policy when the wall clock went backwards.
+	ClockRollbackError struct {
+		Last    time.Time
+		Current time.Time
+	}
+)
+
+const (
+	// ClockRollbackHold keeps using the last timestamp seen and
+	// increments the counter until the clock catches up, preserving
+	// ordering.
+	ClockRollbackHold ClockRollbackPolicy = iota
+
+	// ClockRollbackFail returns a *ClockRollbackError.
+	ClockRollbackFail
+)
+
+const (
+	counterMax = 0xFFF
+
+	// MaxShardBits is the maximum number of bits of rand_b which
+	// can be reserved for a shard identifier.
+	MaxShardBits = 32
+)
+
+var (
+	ErrTimestampOutOfRange = errors.New("timestamp out of range")
+
+	_ Generator = (*Gen)(nil)
+
+	defaultGenerator atomic.Pointer[Generator]
+)
+
+func init() {
+	SetDefaultGenerator(NewGen())
+}
+
+// DefaultGenerator returns the generator used by the package-level
+// constructors such as New, NewV4 and NewV7.
+func DefaultGenerator() Generator {
+	return *defaultGenerator.Load()
+}
+
+// SetDefaultGenerator replaces the generator used by the
+// package-level constructors and returns the previous one. It is safe
+// to call concurrently with UUID generation.
+func SetDefaultGenerator(g Generator) Generator {
+	if g == nil {
+		panic("uuid: nil generator")
+	}
+
+	previous := defaultGenerator.Swap(&g)
+	if previous == nil {
+		return nil
+	}
+
+	return *previous
+}
+
+// NewGen returns a new Gen configured with options. By default, the
+// generator reads its entropy from the package-level entropy source
+// (see SetEntropySource) without retrying failed reads, uses time.Now
+// as clock and holds the last timestamp on clock rollback.
+func NewGen(options ...GenOption) *Gen {
+	g := &Gen{
+		rand:           entropy,
+		randConcurrent: true,
+		now:            time.Now,
+		rollbackPolicy: ClockRollbackHold,
+		variant:        VariantRFC9562,
+		observer:       nopObserver{},
+	}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	if g.shardBits > 0 && g.variant != VariantRFC9562 {
+		panic("uuid: shard incompatible with non-RFC 9562 variant")
+	}
+
+	if g.entropyPolicy.Attempts < 1 {
+		g.entropyPolicy.Attempts = 1
+	}
+	g.entropyPolicy.Backoff = max(g.entropyPolicy.Backoff, minEntropyBackoff)
+
+	g.rand = &observedReader{
+		r:          g.rand,
+		observer:   g.observer,
+		policy:     g.entropyPolicy,
+		concurrent: g.randConcurrent,
+	}
+
+	return g
+}
+
+// WithRandReader sets the entropy source of the generator. As r is
+// not required to be safe for concurrent use, reads from r are
+// serialized.
+func WithRandReader(r io.Reader) GenOption {
+	return func(g *Gen) {
+		g.rand = r
+		g.randConcurrent = false
+	}
+}
+
+// withConcurrentRandReader is like WithRandReader for readers which
+// are safe for concurrent use, such as crypto/rand.
+func withConcurrentRandReader(r io.Reader) GenOption {
+	return func(g *Gen) {
+		g.rand = r
+		g.randConcurrent = true
+	}
+}
+
+// WithEntropyRetry sets the policy applied when reading the entropy
+// source fails. Entropy is read, and reads retried, without holding
+// the generator lock, so that a failing source does not block callers
+// of the same Gen which do not need it. Every failed attempt is
+// reported to the observer.
+func WithEntropyRetry(p EntropyRetryPolicy) GenOption {
+	return func(g *Gen) {
+		g.entropyPolicy = p
+	}
+}
+
+// WithTimeFunc sets the function used by the generator to read the
+// current time.
+func WithTimeFunc(fn func() time.Time) GenOption {
+	return func(g *Gen) {
+		g.now = fn
+	}
+}
+
+// WithNode sets the node identifier of version 1, 2 and 6 UUIDs. By
+// default, a random node identifier with the multicast bit set is
+// used, as recommended by RFC 9562 section 6.10.
+func WithNode(node [6]byte) GenOption {
+	return func(g *Gen) {
+		g.node = node
+		g.nodeSet = true
+	}
+}
+
+// WithObserver sets the observer notified of UUID generations,
+// entropy failures and clock regressions.
+func WithObserver(o Observer) GenOption {
+	return func(g *Gen) {
+		g.observer = o
+	}
+}
+
+// WithClockRollbackPolicy sets the behavior of the generator when the
+// wall clock goes backwards.
+func WithClockRollbackPolicy(p ClockRollbackPolicy) GenOption {
+	return func(g *Gen) {
+		g.rollbackPolicy = p
+	}
+}
+
+// WithShard stores id in the leading bits bits of rand_b of version 7
+// UUIDs, so that multiple generators writing in the same millisecond
+// cannot collide and the origin of a UUID can be
+// recovered with UUID.Shard. It panics if bits is not between 1 and
+// MaxShardBits or if id does not fit in bits.
+func WithShard(bits int, id uint64) GenOption {
+	if bits < 1 || bits > MaxShardBits {
+		panic(fmt.Sprintf("uuid: invalid shard bits %d", bits))
+	}
+
+	if id >= 1<<bits {
+		panic(fmt.Sprintf("uuid: shard id %d does not fit in %d bits", id, bits))
+	}
+
+	return func(g *Gen) {
+		g.shardBits = bits
+		g.shardID = id
+	}
+}
+
+// WithDescendingOrder makes the generator produce UUIDs sorting
+// newest-first instead of version 7 UUIDs: the timestamp and counter
+// are bit-complemented, and the UUIDs are stamped as version 8 since
+// they do not follow the version 7 layout. This suits stores which
+// only support ascending key scans but serve "latest items" queries.
+// The creation time is recovered with UUID.DescendingTimestamp.
+func WithDescendingOrder() GenOption {
+	return func(g *Gen) {
+		g.descending = true
+	}
+}
+
+// WithEpoch makes the generator count the milliseconds of the
+// timestamp from epoch instead of the Unix epoch. A recent epoch
+// extends the range of the 48-bit timestamp and obscures absolute
+// creation times. The layout and ordering of version 7 UUIDs are
+// kept, but the UUIDs are stamped as version 8 since their timestamp
+// is not a Unix one; it is recovered with UUID.TimestampFromEpoch.
+// Generating a UUID before epoch, or 2^48 milliseconds or more after
+// it, returns ErrTimestampOutOfRange.
+func WithEpoch(epoch time.Time) GenOption {
+	return func(g *Gen) {
+		g.epoch = epoch.UnixMilli()
+	}
+}
+
+// WithMicrosoftVariant makes the generator stamp UUIDs with the
+// reserved Microsoft variant (0b110) instead of the RFC 9562 one, as
+// required by some legacy COM and Active Directory integrations. It
+// costs one bit of randomness or clock sequence, cannot be combined
+// with WithShard, and such UUIDs are rejected by ParseStrict. Do not
+// use it unless a consumer requires it.
+func WithMicrosoftVariant() GenOption {
+	return func(g *Gen) {
+		g.variant = VariantMicrosoft
+	}
+}
+
+// WithTimestampJitter adds a random offset between -d and d to the
+// timestamp of every version 7 UUID, so that it only reveals an
+// approximate creation time. UUIDs generated by the same Gen remain
+// strictly increasing, which may push their timestamp further ahead
+// of the clock. It panics if d is negative.
+func WithTimestampJitter(d time.Duration) GenOption {
+	if d < 0 {
+		panic("uuid: negative timestamp jitter")
+	}
+
+	return func(g *Gen) {
+		g.jitter = d.Milliseconds()
+	}
+}
+
+// WithTimestampGranularity truncates the timestamp of every version 7
+// UUID to a multiple of d, such as time.Hour, so that UUIDs align with
+// time-partitioned tables and only reveal a coarse creation time. The
+// 12 bits of rand_a are then random instead of holding a counter:
+// UUIDs remain ordered from one period to the next but not within a
+// period. It panics if d is negative or not a whole number of
+// milliseconds.
+func WithTimestampGranularity(d time.Duration) GenOption {
+	if d < 0 || d%time.Millisecond != 0 {
+		panic("uuid: invalid timestamp granularity")
+	}
+
+	return func(g *Gen) {
+		g.granularity = d.Milliseconds()
+	}
+}
+
+// Error implements error.
+func (e *ClockRollbackError) Error() string {
+	return fmt.Sprintf(
+		"clock rollback: current time %s is before last time %s",
+		e.Current.Format(time.RFC3339Nano),
+		e.Last.Format(time.RFC3339Nano),
+	)
+}
+
+// NewV4 returns a random (version 4) UUID.
+func (g *Gen) NewV4() (UUID, error) {
+	uuid, err := NewV4From(g.rand)
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.SetVariant(g.variant)
+	g.observer.Generated(V4)
+
+	return uuid, nil
+}
+
+// NewV1 returns a Gregorian time-based (version 1) UUID.
+func (g *Gen) NewV1() (UUID, error) {
+	var uuid UUID
+
+	timestamp, clockSeq, err := g.nextGregorian()
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.setV1Timestamp(timestamp)
+	uuid.setClockSeqAndNode(clockSeq, g.node)
+
+	uuid.SetVariant(g.variant)
+	g.observer.Generated(V1)
+
+	return uuid, nil
+}
+
+// NewV2 returns a DCE Security (version 2) UUID embedding id, a POSIX
+// UID or GID for the Person and Group domains.
+func (g *Gen) NewV2(domain Domain, id uint32) (UUID, error) {
+	var uuid UUID
+
+	timestamp, clockSeq, err := g.nextGregorian()
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.setV1Timestamp(timestamp)
+	uuid.setClockSeqAndNode(clockSeq, g.node)
+	uuid.SetVersion(V2)
+
+	binary.BigEndian.PutUint32(uuid[0:4], id)
+	uuid[9] = byte(domain)
+
+	uuid.SetVariant(g.variant)
+	g.observer.Generated(V2)
+
+	return uuid, nil
+}
+
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID.
+func (g *Gen) NewV6() (UUID, error) {
+	var uuid UUID
+
+	timestamp, clockSeq, err := g.nextGregorian()
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.setV6Timestamp(timestamp)
+	uuid.setClockSeqAndNode(clockSeq, g.node)
+
+	uuid.SetVariant(g.variant)
+	g.observer.Generated(V6)
+
+	return uuid, nil
+}
+
+func (g *Gen) nextGregorian() (uint64, uint16, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.initGregorian(); err != nil {
+		return 0, 0, err
+	}
+
+	now := g.now()
+
+	timestamp := gregorianTimestamp(now)
+	if timestamp < g.lastGregorian {
+		g.observer.ClockRegression(gregorianTime(g.lastGregorian), now)
+	}
+
+	if timestamp <= g.lastGregorian {
+		g.clockSeq = (g.clockSeq + 1) & 0x3FFF
+	}
+	g.lastGregorian = timestamp
+
+	if err := g.saveState(); err != nil {
+		return 0, 0, err
+	}
+
+	return timestamp, g.clockSeq, nil
+}
+
+// ClockSeqAndNode returns the clock sequence and node identifier of
+// the version 1, 2 and 6 UUIDs generated by g, initializing them if
+// needed. Unlike generating a UUID, it does not advance the clock
+// sequence.
+func (g *Gen) ClockSeqAndNode() (uint16, [6]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.initGregorian(); err != nil {
+		return 0, [6]byte{}, err
+	}
+
+	return g.clockSeq, g.node, nil
+}
+
+// initGregorian loads the saved state of g and initializes its clock
+// sequence and node identifier if needed. It must be called with g.mu
+// held, which is released while reading entropy, as reads may be
+// retried.
+func (g *Gen) initGregorian() error {
+	var buf [8]byte
+
+	if err := g.loadState(); err != nil {
+		return err
+	}
+
+	if g.gregorianInit {
+		return nil
+	}
+
+	g.mu.Unlock()
+	_, err := io.ReadFull(g.rand, buf[:])
+	g.mu.Lock()
+
+	if err != nil {
+		return err
+	}
+
+	if !g.gregorianInit {
+		g.clockSeq = binary.BigEndian.Uint16(buf[:2]) & 0x3FFF
+		if !g.nodeSet {
+			copy(g.node[:], buf[2:])
+			g.node[0] |= 0x01
+		}
+
+		g.gregorianInit = true
+	}
+
+	return nil
+}
+
+// NewV7 returns a time-ordered (version 7) UUID greater than any
+// other version 7 UUID previously returned by g. It returns
+// ErrTimestampOutOfRange if the timestamp does not fit in 48 bits.
+func (g *Gen) NewV7() (UUID, error) {
+	var uuid UUID
+
+	if _, err := io.ReadFull(g.rand, uuid[6:]); err != nil {
+		return Nil, err
+	}
+
+	jitter, err := g.readJitter()
+	if err != nil {
+		return Nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.nextTimestamp(binary.BigEndian.Uint16(uuid[6:8]), jitter); err != nil {
+		return Nil, err
+	}
+
+	if g.descending {
+		binary.BigEndian.PutUint64(uuid[:8], ^(g.lastTimestamp<<16 | uint64(g.counter)))
+	} else {
+		binary.BigEndian.PutUint64(uuid[:8], g.lastTimestamp<<16|uint64(g.counter))
+	}
+
+	if g.shardBits > 0 {
+		shift := 62 - g.shardBits
+		randB := binary.BigEndian.Uint64(uuid[8:])
+		randB = g.shardID<<shift | randB&(1<<shift-1)
+		binary.BigEndian.PutUint64(uuid[8:], randB)
+	}
+
+	if g.descending || g.epoch != 0 {
+		uuid.SetVersion(V8)
+	} else {
+		uuid.SetVersion(V7)
+	}
+	uuid.SetVariant(g.variant)
+
+	g.observer.Generated(uuid.Version())
+
+	return uuid, nil
+}
+
+// readJitter reads the random value from which nextTimestamp derives
+// the timestamp offset, if g is configured with WithTimestampJitter.
+// It must be called without holding g.mu.
+func (g *Gen) readJitter() (uint64, error) {
+	var buf [8]byte
+
+	if g.jitter == 0 {
+		return 0, nil
+	}
+
+	if _, err := io.ReadFull(g.rand, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// nextTimestamp advances the millisecond timestamp and the 12-bit
+// counter shared by time-ordered UUIDs, seeding the counter with seed
+// on a new millisecond, or on every call with a coarse granularity.
+// jitter is the value returned by readJitter. It returns
+// ErrTimestampOutOfRange if the timestamp does not fit in 48 bits. It
+// must be called with g.mu held.
+func (g *Gen) nextTimestamp(seed uint16, jitter uint64) error {
+	if err := g.loadState(); err != nil {
+		return err
+	}
+
+	now := g.now()
+
+	millis := now.UnixMilli() - g.epoch
+	if millis < 0 {
+		return ErrTimestampOutOfRange
+	}
+
+	if millis < g.lastClock {
+		last := time.UnixMilli(g.lastClock + g.epoch)
+		g.observer.ClockRegression(last, now)
+
+		if g.rollbackPolicy == ClockRollbackFail {
+			return &ClockRollbackError{Last: last, Current: now}
+		}
+	}
+	g.lastClock = millis
+
+	if g.jitter > 0 {
+		offset := int64(jitter%uint64(2*g.jitter+1)) - g.jitter
+		millis = max(millis+offset, 0)
+	}
+
+	timestamp := uint64(millis)
+	if timestamp >= 1<<48 {
+		return ErrTimestampOutOfRange
+	}
+
+	if g.granularity > 1 {
+		// Never go back to a previous period, even if the clock
+		// did, and use the seed as is instead of a counter.
+		timestamp = max(timestamp, g.lastTimestamp)
+		g.lastTimestamp = timestamp - timestamp%uint64(g.granularity)
+		g.counter = seed & counterMax
+
+		return g.saveState()
+	}
+
+	switch {
+	case timestamp > g.lastTimestamp:
+		g.lastTimestamp = timestamp
+		// Seed the counter, keeping its most significant bit
+		// cleared to leave room for increments.
+		g.counter = seed & (counterMax >> 1)
+
+	case g.counter == counterMax && g.lastTimestamp+1 >= 1<<48:
+		return ErrTimestampOutOfRange
+
+	default:
+		g.counter++
+		if g.counter > counterMax {
+			// The counter overflowed: borrow the next
+			// millisecond, as allowed by RFC 9562.
+			g.lastTimestamp++
+			g.counter = 0
+		}
+	}
+
+	return g.saveState()
+}
+
+// NewV7At returns a version 7 UUID embedding t, truncated to the
+// millisecond, with random rand_a and rand_b. It is intended to
+// backfill historical records: unlike NewV7, the result is not
+// ordered relatively to other UUIDs generated by g. It returns
+// ErrTimestampOutOfRange if t is before the Unix epoch or does not fit
+// in 48 bits.
+func (g *Gen) NewV7At(t time.Time) (UUID, error) {
+	uuid, err := newV7At(g.rand, t)
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.SetVariant(g.variant)
+	g.observer.Generated(V7)
+
+	return uuid, nil
+}
+
+// NewV7At is like Gen.NewV7At but reads its entropy from the
+// package-level entropy source.
+func NewV7At(t time.Time) (UUID, error) {
+	return newV7At(entropy, t)
+}
+
+func newV7At(r io.Reader, t time.Time) (UUID, error) {
+	var uuid UUID
+
+	timestamp := t.UnixMilli()
+	if timestamp < 0 || timestamp >= 1<<48 {
+		return Nil, ErrTimestampOutOfRange
+	}
+
+	if _, err := io.ReadFull(r, uuid[6:]); err != nil {
+		return Nil, err
+	}
+
+	binary.BigEndian.PutUint16(uuid[0:2], uint16(timestamp>>32))
+	binary.BigEndian.PutUint32(uuid[2:6], uint32(timestamp))
+
+	uuid.SetVersion(V7)
+	uuid.SetVariant(VariantRFC9562)
+
+	return uuid, nil
+}
@@ -0,0 +1,8 @@
+module go.gearno.de/crypto/uuid/gofrsuuid
+
+go 1.25.0
+
+require (
+	github.com/gofrs/uuid/v5 v5.5.1
+	go.gearno.de/crypto/uuid v0.1.0
+)
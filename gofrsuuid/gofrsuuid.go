@@ -0,0 +1,175 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package gofrsuuid converts UUIDs between package uuid and
+// github.com/gofrs/uuid, and adapts a uuid.Generator to the Generator
+// interface of the latter, easing the migration of code bases using
+// both.
+package gofrsuuid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	gofrs "github.com/gofrs/uuid/v5"
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	// Gen implements the gofrs Generator interface on top of a
+	// uuid.Generator.
+	Gen struct {
+		gen uuid.Generator
+	}
+
+	// clockSeqAndNoder is implemented by generators exposing the
+	// state of their Gregorian time-based UUIDs, such as uuid.Gen.
+	clockSeqAndNoder interface {
+		ClockSeqAndNode() (uint16, [6]byte, error)
+	}
+)
+
+var (
+	ErrNoClockSeqAndNode = fmt.Errorf("generator does not expose its clock sequence and node: %w", errors.ErrUnsupported)
+
+	_ gofrs.Generator = (*Gen)(nil)
+)
+
+// From converts a gofrs UUID.
+func From(u gofrs.UUID) uuid.UUID {
+	return uuid.UUID(u)
+}
+
+// To converts u to a gofrs UUID.
+func To(u uuid.UUID) gofrs.UUID {
+	return gofrs.UUID(u)
+}
+
+// FromNull converts a gofrs NullUUID, returning false if it is not
+// valid.
+func FromNull(n gofrs.NullUUID) (uuid.UUID, bool) {
+	if !n.Valid {
+		return uuid.Nil, false
+	}
+
+	return uuid.UUID(n.UUID), true
+}
+
+// ToNull converts u to a gofrs NullUUID, which is valid if valid is
+// true.
+func ToNull(u uuid.UUID, valid bool) gofrs.NullUUID {
+	if !valid {
+		return gofrs.NullUUID{}
+	}
+
+	return gofrs.NullUUID{UUID: gofrs.UUID(u), Valid: true}
+}
+
+// NewGen returns a Gen generating UUIDs with gen, or with the default
+// generator of package uuid if gen is nil.
+func NewGen(gen uuid.Generator) *Gen {
+	return &Gen{gen: gen}
+}
+
+func (g *Gen) generator() uuid.Generator {
+	if g.gen == nil {
+		return uuid.DefaultGenerator()
+	}
+
+	return g.gen
+}
+
+// NewV1 implements gofrs.Generator.
+func (g *Gen) NewV1() (gofrs.UUID, error) {
+	return convert(g.generator().NewV1())
+}
+
+// NewV1AtTime implements gofrs.Generator. The clock sequence and node
+// identifier are those of the underlying generator, which must have a
+// ClockSeqAndNode method like uuid.Gen; ErrNoClockSeqAndNode is
+// returned otherwise.
+func (g *Gen) NewV1AtTime(t time.Time) (gofrs.UUID, error) {
+	clockSeq, node, err := g.clockSeqAndNode()
+	if err != nil {
+		return gofrs.Nil, err
+	}
+
+	return convert(uuid.NewV1At(t, clockSeq, node))
+}
+
+// NewV3 implements gofrs.Generator.
+func (g *Gen) NewV3(ns gofrs.UUID, name string) gofrs.UUID {
+	return gofrs.UUID(uuid.NewV3(uuid.UUID(ns), name))
+}
+
+// NewV4 implements gofrs.Generator.
+func (g *Gen) NewV4() (gofrs.UUID, error) {
+	return convert(g.generator().NewV4())
+}
+
+// NewV5 implements gofrs.Generator.
+func (g *Gen) NewV5(ns gofrs.UUID, name string) gofrs.UUID {
+	return gofrs.UUID(uuid.NewV5(uuid.UUID(ns), name))
+}
+
+// NewV6 implements gofrs.Generator.
+func (g *Gen) NewV6() (gofrs.UUID, error) {
+	return convert(g.generator().NewV6())
+}
+
+// NewV6AtTime implements gofrs.Generator like NewV1AtTime.
+func (g *Gen) NewV6AtTime(t time.Time) (gofrs.UUID, error) {
+	clockSeq, node, err := g.clockSeqAndNode()
+	if err != nil {
+		return gofrs.Nil, err
+	}
+
+	return convert(uuid.NewV6At(t, clockSeq, node))
+}
+
+// NewV7 implements gofrs.Generator.
+func (g *Gen) NewV7() (gofrs.UUID, error) {
+	return convert(g.generator().NewV7())
+}
+
+// NewV7AtTime implements gofrs.Generator. The result is not ordered
+// relatively to the UUIDs returned by NewV7.
+func (g *Gen) NewV7AtTime(t time.Time) (gofrs.UUID, error) {
+	if gen, ok := g.generator().(*uuid.Gen); ok {
+		return convert(gen.NewV7At(t))
+	}
+
+	return convert(uuid.NewV7At(t))
+}
+
+// NewV8 implements gofrs.Generator.
+func (g *Gen) NewV8(customA, customB, customC []byte) (gofrs.UUID, error) {
+	return gofrs.NewV8(customA, customB, customC)
+}
+
+func convert(u uuid.UUID, err error) (gofrs.UUID, error) {
+	return gofrs.UUID(u), err
+}
+
+func (g *Gen) clockSeqAndNode() (uint16, [6]byte, error) {
+	gen, ok := g.generator().(clockSeqAndNoder)
+	if !ok {
+		return 0, [6]byte{}, ErrNoClockSeqAndNode
+	}
+
+	return gen.ClockSeqAndNode()
+}
@@ -0,0 +1,545 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package googleuuid mirrors the API of github.com/google/uuid on top
+// of package uuid, so that existing code can be migrated by changing
+// its imports only:
+//
+//	import uuid "go.gearno.de/crypto/uuid/googleuuid"
+//
+// Generation goes through the default generator of package uuid, and
+// a UUID converts to and from uuid.UUID without copying.
+//
+// Node identifier and clock sequence configuration, as well as the
+// random pool, are not supported: the corresponding functions are
+// either missing or have no effect.
+package googleuuid
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	// UUID is a 128 bits Universal Unique Identifier.
+	UUID uuid.UUID
+
+	// UUIDs is a slice of UUID.
+	UUIDs []UUID
+
+	// Version is the version of a UUID.
+	Version byte
+
+	// Variant is the variant of a UUID.
+	Variant byte
+
+	// Domain is the local domain of a DCE Security (version 2)
+	// UUID.
+	Domain byte
+
+	// Time is a timestamp in 100 nanoseconds intervals since
+	// 15 October 1582.
+	Time int64
+
+	// NullUUID represents a UUID which may be null, for use with
+	// database/sql.
+	NullUUID struct {
+		UUID  UUID
+		Valid bool
+	}
+)
+
+const (
+	Invalid = Variant(iota)
+	RFC4122
+	Reserved
+	Microsoft
+	Future
+)
+
+const (
+	Person = Domain(uuid.DomainPerson)
+	Group  = Domain(uuid.DomainGroup)
+	Org    = Domain(uuid.DomainOrg)
+)
+
+const (
+	// g1582ns100 is the number of 100 nanoseconds intervals between
+	// 15 October 1582 and the Unix epoch.
+	g1582ns100 = 122192928000000000
+)
+
+var (
+	Nil = UUID(uuid.Nil)
+	Max = UUID(uuid.Max)
+
+	NameSpaceDNS  = UUID(uuid.NamespaceDNS)
+	NameSpaceURL  = UUID(uuid.NamespaceURL)
+	NameSpaceOID  = UUID(uuid.NamespaceOID)
+	NameSpaceX500 = UUID(uuid.NamespaceX500)
+
+	nullJSON = []byte("null")
+)
+
+// New returns a random (version 4) UUID. It panics on failure.
+func New() UUID {
+	return Must(NewRandom())
+}
+
+// NewString returns the string form of New.
+func NewString() string {
+	return New().String()
+}
+
+// NewRandom returns a random (version 4) UUID.
+func NewRandom() (UUID, error) {
+	u, err := uuid.NewV4()
+	return UUID(u), err
+}
+
+// NewRandomFromReader returns a random (version 4) UUID reading its
+// entropy from r.
+func NewRandomFromReader(r io.Reader) (UUID, error) {
+	u, err := uuid.NewV4From(r)
+	return UUID(u), err
+}
+
+// NewUUID returns a Gregorian time-based (version 1) UUID.
+func NewUUID() (UUID, error) {
+	u, err := uuid.NewV1()
+	return UUID(u), err
+}
+
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID.
+func NewV6() (UUID, error) {
+	u, err := uuid.NewV6()
+	return UUID(u), err
+}
+
+// NewV7 returns a time-ordered (version 7) UUID.
+func NewV7() (UUID, error) {
+	u, err := uuid.NewV7()
+	return UUID(u), err
+}
+
+// NewDCESecurity returns a DCE Security (version 2) UUID.
+func NewDCESecurity(domain Domain, id uint32) (UUID, error) {
+	u, err := uuid.NewV2(uuid.Domain(domain), id)
+	return UUID(u), err
+}
+
+// NewDCEPerson returns a DCE Security (version 2) UUID in the Person
+// domain with the UID of the process.
+func NewDCEPerson() (UUID, error) {
+	return NewDCESecurity(Person, uint32(os.Getuid()))
+}
+
+// NewDCEGroup returns a DCE Security (version 2) UUID in the Group
+// domain with the GID of the process.
+func NewDCEGroup() (UUID, error) {
+	return NewDCESecurity(Group, uint32(os.Getgid()))
+}
+
+// NewHash returns a name-based UUID of version version computed with
+// h over space and data.
+func NewHash(h hash.Hash, space UUID, data []byte, version int) UUID {
+	var u uuid.UUID
+
+	h.Reset()
+	h.Write(space[:])
+	h.Write(data)
+	copy(u[:], h.Sum(nil))
+
+	u.SetVersion(uuid.Version(version))
+	u.SetVariant(uuid.VariantRFC9562)
+
+	return UUID(u)
+}
+
+// NewMD5 returns a name-based (version 3) UUID.
+func NewMD5(space UUID, data []byte) UUID {
+	return NewHash(md5.New(), space, data, 3)
+}
+
+// NewSHA1 returns a name-based (version 5) UUID.
+func NewSHA1(space UUID, data []byte) UUID {
+	return NewHash(sha1.New(), space, data, 5)
+}
+
+// SetRand sets the entropy source of package uuid, restoring
+// crypto/rand if r is nil.
+func SetRand(r io.Reader) {
+	if r == nil {
+		r = rand.Reader
+	}
+
+	uuid.SetEntropySource(r)
+}
+
+// EnableRandPool has no effect; use uuid.Pool instead.
+func EnableRandPool() {}
+
+// DisableRandPool has no effect.
+func DisableRandPool() {}
+
+// Must returns u or panics if err is not nil.
+func Must(u UUID, err error) UUID {
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// Parse decodes s, which may be in the canonical form, with or
+// without braces or a "urn:uuid:" prefix, or 32 hexadecimal digits.
+func Parse(s string) (UUID, error) {
+	return ParseBytes([]byte(s))
+}
+
+// ParseBytes is like Parse but takes a byte slice.
+func ParseBytes(b []byte) (UUID, error) {
+	switch len(b) {
+	case 36, 32:
+		// The canonical form or 32 hexadecimal digits.
+
+	case 45:
+		if !bytes.EqualFold(b[:9], []byte("urn:uuid:")) {
+			return Nil, uuid.ErrInvalidFormat
+		}
+		b = b[9:]
+
+	case 38:
+		if b[0] != '{' || b[37] != '}' {
+			return Nil, uuid.ErrInvalidSeparator
+		}
+		b = b[1:37]
+
+	default:
+		return Nil, uuid.ErrInvalidLength
+	}
+
+	u, err := uuid.ParseAny(b)
+	return UUID(u), err
+}
+
+// MustParse is like Parse but panics on error.
+func MustParse(s string) UUID {
+	return Must(Parse(s))
+}
+
+// FromBytes returns the UUID whose binary form is b.
+func FromBytes(b []byte) (UUID, error) {
+	u, err := uuid.FromBytes(b)
+	return UUID(u), err
+}
+
+// Validate returns an error if s is not a valid UUID, as defined by
+// Parse.
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+// IsInvalidLengthError reports whether err was caused by an input of
+// invalid length.
+func IsInvalidLengthError(err error) bool {
+	return errors.Is(err, uuid.ErrInvalidLength)
+}
+
+// String returns the canonical form of u, whatever the text format
+// configured with uuid.SetTextFormat.
+func (u UUID) String() string {
+	return uuid.UUID(u).Format("D")
+}
+
+// URN returns the "urn:uuid:" form of u.
+func (u UUID) URN() string {
+	return "urn:uuid:" + u.String()
+}
+
+// Version returns the version of u.
+func (u UUID) Version() Version {
+	return Version(u[6] >> 4)
+}
+
+// Variant returns the variant of u.
+func (u UUID) Variant() Variant {
+	switch {
+	case u[8]&0xc0 == 0x80:
+		return RFC4122
+	case u[8]&0xe0 == 0xc0:
+		return Microsoft
+	case u[8]&0xe0 == 0xe0:
+		return Future
+	default:
+		return Reserved
+	}
+}
+
+// Time returns the timestamp of a version 1, 2, 6 or 7 UUID.
+func (u UUID) Time() Time {
+	switch u.Version() {
+	case 6:
+		t := binary.BigEndian.Uint64(u[:8])
+		return Time(t>>4&^0xFFF | t&0xFFF)
+
+	case 7:
+		ms := binary.BigEndian.Uint64(u[:8]) >> 16
+		return Time(ms*10000 + g1582ns100)
+
+	default:
+		t := uint64(binary.BigEndian.Uint32(u[0:4]))
+		t |= uint64(binary.BigEndian.Uint16(u[4:6])) << 32
+		t |= uint64(binary.BigEndian.Uint16(u[6:8])&0xFFF) << 48
+		return Time(t)
+	}
+}
+
+// ClockSequence returns the clock sequence of a version 1, 2 or 6
+// UUID.
+func (u UUID) ClockSequence() int {
+	return int(binary.BigEndian.Uint16(u[8:10]) & 0x3fff)
+}
+
+// NodeID returns the node identifier of a version 1, 2 or 6 UUID.
+func (u UUID) NodeID() []byte {
+	node := make([]byte, 6)
+	copy(node, u[10:])
+	return node
+}
+
+// Domain returns the local domain of a version 2 UUID.
+func (u UUID) Domain() Domain {
+	return Domain(uuid.UUID(u).Domain())
+}
+
+// ID returns the local identifier of a version 2 UUID.
+func (u UUID) ID() uint32 {
+	return uuid.UUID(u).DomainID()
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the
+// canonical form of u.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UUID) UnmarshalText(data []byte) error {
+	id, err := ParseBytes(data)
+	if err != nil {
+		return err
+	}
+
+	*u = id
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (u UUID) MarshalBinary() ([]byte, error) {
+	return uuid.UUID(u).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (u *UUID) UnmarshalBinary(data []byte) error {
+	return (*uuid.UUID)(u).UnmarshalBinary(data)
+}
+
+// Scan implements sql.Scanner. It accepts strings and byte slices in
+// textual or binary form; empty values leave u unchanged.
+func (u *UUID) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+
+	case string:
+		if src == "" {
+			return nil
+		}
+
+		return u.UnmarshalText([]byte(src))
+
+	case []byte:
+		switch len(src) {
+		case 0:
+			return nil
+		case 16:
+			copy(u[:], src)
+			return nil
+		default:
+			return u.UnmarshalText(src)
+		}
+
+	default:
+		return fmt.Errorf("Scan: unable to scan type %T into UUID", src)
+	}
+}
+
+// Value implements driver.Valuer, returning the canonical form of u.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Strings returns the canonical form of every UUID of uuids.
+func (uuids UUIDs) Strings() []string {
+	s := make([]string, len(uuids))
+	for i, u := range uuids {
+		s[i] = u.String()
+	}
+
+	return s
+}
+
+// String implements fmt.Stringer.
+func (v Version) String() string {
+	if v > 15 {
+		return fmt.Sprintf("BAD_VERSION_%d", v)
+	}
+
+	return fmt.Sprintf("VERSION_%d", v)
+}
+
+// String implements fmt.Stringer.
+func (v Variant) String() string {
+	switch v {
+	case RFC4122:
+		return "RFC4122"
+	case Reserved:
+		return "Reserved"
+	case Microsoft:
+		return "Microsoft"
+	case Future:
+		return "Future"
+	case Invalid:
+		return "Invalid"
+	}
+
+	return fmt.Sprintf("BadVariant%d", int(v))
+}
+
+// String implements fmt.Stringer.
+func (d Domain) String() string {
+	return uuid.Domain(d).String()
+}
+
+// UnixTime returns t as seconds and nanoseconds since the Unix epoch.
+func (t Time) UnixTime() (sec, nsec int64) {
+	sec = int64(t - g1582ns100)
+	nsec = (sec % 10000000) * 100
+	sec /= 10000000
+
+	return sec, nsec
+}
+
+// Scan implements sql.Scanner.
+func (nu *NullUUID) Scan(src any) error {
+	if src == nil {
+		nu.UUID, nu.Valid = Nil, false
+		return nil
+	}
+
+	err := nu.UUID.Scan(src)
+	nu.Valid = err == nil
+
+	return err
+}
+
+// Value implements driver.Valuer.
+func (nu NullUUID) Value() (driver.Value, error) {
+	if !nu.Valid {
+		return nil, nil
+	}
+
+	return nu.UUID.Value()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (nu NullUUID) MarshalBinary() ([]byte, error) {
+	if nu.Valid {
+		return nu.UUID[:], nil
+	}
+
+	return []byte(nil), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (nu *NullUUID) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return uuid.ErrInvalidLength
+	}
+
+	copy(nu.UUID[:], data)
+	nu.Valid = true
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (nu NullUUID) MarshalText() ([]byte, error) {
+	if nu.Valid {
+		return nu.UUID.MarshalText()
+	}
+
+	return nullJSON, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (nu *NullUUID) UnmarshalText(data []byte) error {
+	id, err := ParseBytes(data)
+	if err != nil {
+		nu.Valid = false
+		return err
+	}
+
+	nu.UUID, nu.Valid = id, true
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (nu NullUUID) MarshalJSON() ([]byte, error) {
+	if nu.Valid {
+		return json.Marshal(nu.UUID)
+	}
+
+	return nullJSON, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (nu *NullUUID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullJSON) {
+		*nu = NullUUID{}
+		return nil
+	}
+
+	err := json.Unmarshal(data, &nu.UUID)
+	nu.Valid = err == nil
+
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// gregorianOffset is the number of 100-nanosecond intervals
+	// between the Gregorian epoch (1582-10-15) and the Unix epoch.
+	gregorianOffset = 0x01B21DD213814000
+
+	// Cassandra compares the clock sequence and node of timeuuids
+	// as signed bytes, making these the lowest and highest values.
+	minTimeUUIDClockSeqAndNode = 0x8080808080808080
+	maxTimeUUIDClockSeqAndNode = 0x7F7F7F7F7F7F7F7F
+)
+
+// MinTimeUUID returns the smallest time-based (version 1) UUID
+// for t truncated to the millisecond, following the semantics of the
+// minTimeuuid function of Cassandra.
+func MinTimeUUID(t time.Time) UUID {
+	var uuid UUID
+
+	uuid.setV1Timestamp(gregorianTimestamp(time.UnixMilli(t.UnixMilli())))
+	binary.BigEndian.PutUint64(uuid[8:], minTimeUUIDClockSeqAndNode)
+
+	return uuid
+}
+
+// MaxTimeUUID returns the greatest time-based (version 1) UUID for
+// t truncated to the millisecond, following the semantics of the
+// maxTimeuuid function of Cassandra.
+func MaxTimeUUID(t time.Time) UUID {
+	var uuid UUID
+
+	uuid.setV1Timestamp(gregorianTimestamp(time.UnixMilli(t.UnixMilli()+1)) - 1)
+	binary.BigEndian.PutUint64(uuid[8:], maxTimeUUIDClockSeqAndNode)
+
+	return uuid
+}
+
+// NewV1At returns the Gregorian time-based (version 1) UUID for t,
+// clockSeq and node. Only the 14 least significant bits of clockSeq
+// are used. Since it does not read any entropy, it is suited to
+// deterministic replays and to re-serializing historical events. It
+// returns ErrTimestampOutOfRange if t is before the Gregorian epoch
+// or does not fit in 60 bits.
+func NewV1At(t time.Time, clockSeq uint16, node [6]byte) (UUID, error) {
+	var uuid UUID
+
+	timestamp, err := checkedGregorianTimestamp(t)
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.setV1Timestamp(timestamp)
+	uuid.setClockSeqAndNode(clockSeq, node)
+
+	return uuid, nil
+}
+
+// NewV6At is like NewV1At but returns a reordered Gregorian
+// time-based (version 6) UUID.
+func NewV6At(t time.Time, clockSeq uint16, node [6]byte) (UUID, error) {
+	var uuid UUID
+
+	timestamp, err := checkedGregorianTimestamp(t)
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.setV6Timestamp(timestamp)
+	uuid.setClockSeqAndNode(clockSeq, node)
+
+	return uuid, nil
+}
+
+func checkedGregorianTimestamp(t time.Time) (uint64, error) {
+	// Bounds are checked in seconds first, to avoid overflows when
+	// converting t to 100-nanosecond intervals.
+	seconds := t.Unix() + gregorianOffset/1e7
+	if seconds < 0 || seconds >= (1<<60)/10000000 {
+		return 0, ErrTimestampOutOfRange
+	}
+
+	timestamp := gregorianTimestamp(t)
+	if timestamp >= 1<<60 {
+		return 0, ErrTimestampOutOfRange
+	}
+
+	return timestamp, nil
+}
+
+// gregorianTimestamp returns the number of 100-nanosecond intervals
+// between the Gregorian epoch and t.
+func gregorianTimestamp(t time.Time) uint64 {
+	return uint64(t.Unix()*1e7+int64(t.Nanosecond()/100)) + gregorianOffset
+}
+
+func gregorianTime(timestamp uint64) time.Time {
+	unix := int64(timestamp - gregorianOffset)
+
+	return time.Unix(unix/1e7, unix%1e7*100)
+}
+
+// setV1Timestamp stores the 60-bit timestamp in the time_low,
+// time_mid and time_high fields and sets the version to V1.
+func (uuid *UUID) setV1Timestamp(timestamp uint64) {
+	binary.BigEndian.PutUint32(uuid[0:4], uint32(timestamp))
+	binary.BigEndian.PutUint16(uuid[4:6], uint16(timestamp>>32))
+	binary.BigEndian.PutUint16(uuid[6:8], uint16(timestamp>>48))
+
+	uuid.SetVersion(V1)
+}
+
+// setV6Timestamp stores the 60-bit timestamp in the time_high,
+// time_mid and time_low fields and sets the version to V6.
+func (uuid *UUID) setV6Timestamp(timestamp uint64) {
+	binary.BigEndian.PutUint32(uuid[0:4], uint32(timestamp>>28))
+	binary.BigEndian.PutUint16(uuid[4:6], uint16(timestamp>>12))
+	binary.BigEndian.PutUint16(uuid[6:8], uint16(timestamp&0x0FFF))
+
+	uuid.SetVersion(V6)
+}
+
+func (uuid *UUID) setClockSeqAndNode(clockSeq uint16, node [6]byte) {
+	binary.BigEndian.PutUint16(uuid[8:10], clockSeq&0x3FFF)
+	copy(uuid[10:], node[:])
+
+	uuid.SetVariant(VariantRFC9562)
+}
+
+func (uuid UUID) v6Timestamp() uint64 {
+	return uint64(binary.BigEndian.Uint32(uuid[0:4]))<<28 |
+		uint64(binary.BigEndian.Uint16(uuid[4:6]))<<12 |
+		uint64(binary.BigEndian.Uint16(uuid[6:8])&0x0FFF)
+}
+
+func (uuid UUID) v1Timestamp() uint64 {
+	return uint64(binary.BigEndian.Uint32(uuid[0:4])) |
+		uint64(binary.BigEndian.Uint16(uuid[4:6]))<<32 |
+		uint64(binary.BigEndian.Uint16(uuid[6:8])&0x0FFF)<<48
+}
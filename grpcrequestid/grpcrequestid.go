@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package grpcrequestid propagates UUID request identifiers through
+// gRPC calls, mirroring the HTTP middleware of package requestid: the
+// identifiers are stored in contexts with uuid.NewContext and carried
+// in the x-request-id metadata.
+package grpcrequestid
+
+import (
+	"context"
+
+	"go.gearno.de/crypto/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+	serverStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+)
+
+const (
+	// MetadataKey is the gRPC metadata key carrying the request
+	// identifier.
+	MetadataKey = "x-request-id"
+)
+
+// UnaryServerInterceptor returns an interceptor assigning a request
+// identifier to every unary call. The identifier is taken from the
+// incoming metadata when it contains a valid UUID, and is a new
+// version 7 UUID otherwise. It is stored in the context of the call
+// and sent back in the response header.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := serverContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is like UnaryServerInterceptor for
+// streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := serverContext(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor sending the request
+// identifier of the context of every unary call in its outgoing
+// metadata, generating a new version 7 UUID if the context does not
+// carry one.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := clientContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor is like UnaryClientInterceptor for
+// streaming calls.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := clientContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// Context implements grpc.ServerStream.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func serverContext(ctx context.Context) (context.Context, error) {
+	var (
+		id  uuid.UUID
+		err = uuid.ErrInvalidFormat
+	)
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			id, err = uuid.Parse(values[0])
+		}
+	}
+
+	if err != nil {
+		id, err = uuid.NewV7()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id.String())); err != nil {
+		return nil, err
+	}
+
+	return uuid.NewContext(ctx, id), nil
+}
+
+func clientContext(ctx context.Context) (context.Context, error) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(MetadataKey)) > 0 {
+		return ctx, nil
+	}
+
+	id, ok := uuid.FromContext(ctx)
+	if !ok {
+		var err error
+
+		id, err = uuid.NewV7()
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = uuid.NewContext(ctx, id)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, id.String()), nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// NewSequentialGUID returns a custom (version 8) UUID which sorts in
+// generation order under the uniqueidentifier ordering of SQL Server,
+// like the GUIDs produced by NEWSEQUENTIALID, so that inserts into a
+// clustered index do not cause page splits.
+//
+// SQL Server compares the last six bytes first, then the two bytes
+// before them, the remaining groups being the least significant. The
+// millisecond timestamp is therefore stored in the last six bytes and
+// the counter of g in the clock sequence field; other bits are random.
+// The ordering of the textual and binary forms is not preserved.
+func (g *Gen) NewSequentialGUID() (UUID, error) {
+	var uuid UUID
+
+	if _, err := io.ReadFull(g.rand, uuid[:10]); err != nil {
+		return Nil, err
+	}
+
+	jitter, err := g.readJitter()
+	if err != nil {
+		return Nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.nextTimestamp(binary.BigEndian.Uint16(uuid[8:10]), jitter); err != nil {
+		return Nil, err
+	}
+
+	binary.BigEndian.PutUint16(uuid[8:10], g.counter)
+	binary.BigEndian.PutUint16(uuid[10:12], uint16(g.lastTimestamp>>32))
+	binary.BigEndian.PutUint32(uuid[12:16], uint32(g.lastTimestamp))
+
+	uuid.SetVersion(V8)
+	uuid.SetVariant(g.variant)
+
+	g.observer.Generated(V8)
+
+	return uuid, nil
+}
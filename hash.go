@@ -0,0 +1,49 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+)
+
+// Hash64 returns a well-mixed 64-bit hash of uuid, suitable for hash
+// tables, consistent hashing rings and cache keys. Unlike hash/maphash
+// the hash is not seeded: it is stable across processes and
+// platforms, and must not be relied upon against adversarial inputs.
+func (uuid UUID) Hash64() uint64 {
+	hi := binary.BigEndian.Uint64(uuid[:8])
+	lo := binary.BigEndian.Uint64(uuid[8:])
+
+	return mix64(hi ^ mix64(lo+0x9E3779B97F4A7C15))
+}
+
+// Hash32 returns a well-mixed 32-bit hash of uuid, folded from
+// Hash64.
+func (uuid UUID) Hash32() uint32 {
+	h := uuid.Hash64()
+
+	return uint32(h ^ h>>32)
+}
+
+// mix64 is the finalizer of SplitMix64, a bijection with good
+// avalanche properties.
+func mix64(x uint64) uint64 {
+	x = (x ^ x>>30) * 0xBF58476D1CE4E5B9
+	x = (x ^ x>>27) * 0x94D049BB133111EB
+
+	return x ^ x>>31
+}
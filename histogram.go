@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"time"
+)
+
+type (
+	// Histogram counts UUIDs by creation time, as extracted from
+	// time-based UUIDs, in buckets of equal width.
+	Histogram struct {
+		// Start is the start of the first bucket and End the end
+		// of the last one.
+		Start time.Time
+		End   time.Time
+
+		// Width is the duration covered by each bucket.
+		Width time.Duration
+
+		// Counts holds the number of UUIDs of every bucket, the
+		// i-th bucket starting at Start + i*Width.
+		Counts []int
+
+		// Min and Max are the earliest and latest timestamps
+		// counted.
+		Min time.Time
+		Max time.Time
+
+		// Total is the number of UUIDs counted, Skipped the
+		// number of UUIDs ignored for lack of a timestamp, and
+		// Outside the number of UUIDs ignored because their
+		// timestamp is out of bounds.
+		Total   int
+		Skipped int
+		Outside int
+	}
+)
+
+// Histogram returns the histogram of the creation time of uuids
+// between from and to, excluded, in buckets of width w, the last
+// bucket being truncated at to if needed. UUIDs of versions other
+// than 1, 6 and 7 and UUIDs created out of bounds are not counted, so
+// that outliers such as corrupted timestamps cannot affect the
+// number of buckets. uuids do not need to be sorted. It panics if w is
+// not positive or if to is not after from.
+func (uuids UUIDs) Histogram(from, to time.Time, w time.Duration) Histogram {
+	if w <= 0 || !to.After(from) {
+		panic("uuid: invalid histogram bounds")
+	}
+
+	h := Histogram{
+		Start:  from,
+		End:    to,
+		Width:  w,
+		Counts: make([]int, (to.Sub(from)+w-1)/w),
+	}
+
+	for _, uuid := range uuids {
+		if !uuid.hasTimestamp() {
+			h.Skipped++
+			continue
+		}
+
+		t := uuid.Timestamp()
+		if t.Before(from) || !t.Before(to) {
+			h.Outside++
+			continue
+		}
+
+		if h.Total == 0 || t.Before(h.Min) {
+			h.Min = t
+		}
+		if h.Total == 0 || t.After(h.Max) {
+			h.Max = t
+		}
+
+		h.Counts[t.Sub(from)/w]++
+		h.Total++
+	}
+
+	return h
+}
+
+// BucketStart returns the start of the i-th bucket.
+func (h Histogram) BucketStart(i int) time.Time {
+	return h.Start.Add(time.Duration(i) * h.Width)
+}
+
+// Rate returns the average number of UUIDs created per second between
+// the earliest and the latest timestamps, or 0 if they are equal.
+func (h Histogram) Rate() float64 {
+	span := h.Max.Sub(h.Min).Seconds()
+	if span == 0 {
+		return 0
+	}
+
+	return float64(h.Total) / span
+}
+
+// BucketRate returns the number of UUIDs created per second during
+// the i-th bucket.
+func (h Histogram) BucketRate(i int) float64 {
+	width := min(h.Width, h.End.Sub(h.BucketStart(i)))
+
+	return float64(h.Counts[i]) / width.Seconds()
+}
+
+// PeakRate returns the highest BucketRate of h.
+func (h Histogram) PeakRate() float64 {
+	var peak float64
+	for i := range h.Counts {
+		peak = max(peak, h.BucketRate(i))
+	}
+
+	return peak
+}
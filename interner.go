@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"sync"
+)
+
+type (
+	// Interner is a bounded cache of UUIDs and their textual form,
+	// for workloads parsing or formatting the same UUIDs over and
+	// over again. Once full, the oldest entries are evicted first.
+	// An Interner is safe for concurrent use.
+	Interner struct {
+		mu      sync.RWMutex
+		window  []internedUUID
+		next    int
+		strings map[string]UUID
+		uuids   map[UUID]string
+	}
+
+	internedUUID struct {
+		uuid UUID
+		text string
+	}
+)
+
+// NewInterner returns an Interner remembering up to size UUIDs.
+func NewInterner(size int) *Interner {
+	if size <= 0 {
+		panic("uuid: invalid interner size")
+	}
+
+	return &Interner{
+		window:  make([]internedUUID, 0, size),
+		strings: make(map[string]UUID, size),
+		uuids:   make(map[UUID]string, size),
+	}
+}
+
+// Parse is like UUID.UnmarshalText, but returns the cached UUID if s
+// was parsed recently.
+func (i *Interner) Parse(s string) (UUID, error) {
+	i.mu.RLock()
+	uuid, found := i.strings[s]
+	i.mu.RUnlock()
+
+	if found {
+		return uuid, nil
+	}
+
+	uuid, err := parseText([]byte(s))
+	if err != nil {
+		return Nil, err
+	}
+
+	i.mu.Lock()
+	i.remember(uuid, s, false)
+	i.mu.Unlock()
+
+	return uuid, nil
+}
+
+// ParseBytes is like Parse but takes a byte slice. Cache hits do not
+// allocate.
+func (i *Interner) ParseBytes(b []byte) (UUID, error) {
+	i.mu.RLock()
+	uuid, found := i.strings[string(b)]
+	i.mu.RUnlock()
+
+	if found {
+		return uuid, nil
+	}
+
+	return i.Parse(string(b))
+}
+
+// String is like UUID.String, but returns the cached string if uuid
+// was formatted recently. Cached strings are not updated when
+// SetTextFormat is called.
+func (i *Interner) String(uuid UUID) string {
+	i.mu.RLock()
+	s, found := i.uuids[uuid]
+	i.mu.RUnlock()
+
+	if found {
+		return s
+	}
+
+	s = uuid.String()
+
+	i.mu.Lock()
+	i.remember(uuid, s, true)
+	i.mu.Unlock()
+
+	return s
+}
+
+// Len returns the number of cached entries.
+func (i *Interner) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.window)
+}
+
+// remember records text as the textual form of uuid. Only strings
+// produced by UUID.String are returned by String, as parsed strings
+// may use any of the accepted forms.
+func (i *Interner) remember(uuid UUID, text string, formatted bool) {
+	if len(i.window) < cap(i.window) {
+		i.window = append(i.window, internedUUID{uuid, text})
+	} else {
+		evicted := i.window[i.next]
+		delete(i.strings, evicted.text)
+		if i.uuids[evicted.uuid] == evicted.text {
+			delete(i.uuids, evicted.uuid)
+		}
+
+		i.window[i.next] = internedUUID{uuid, text}
+		i.next = (i.next + 1) % len(i.window)
+	}
+
+	i.strings[text] = uuid
+	if formatted {
+		i.uuids[uuid] = text
+	}
+}
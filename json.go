@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+//go:build go1.27 && goexperiment.jsonv2
+
+package uuid
+
+// Although GOEXPERIMENT=jsonv2 provides encoding/json/v2 since Go
+// 1.25, this file requires Go 1.27: as the module declares go 1.22,
+// Go 1.27 toolchains reject references to encoding/json/jsontext,
+// recorded as added in Go 1.27, from files whose build constraint does
+// not imply that version, so that "go vet" and "go test" fail. A
+// single constraint cannot serve both, and Go 1.25 and 1.26 are left
+// to MarshalText and UnmarshalText.
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+)
+
+// MarshalJSONTo implements json.MarshalerTo, encoding uuid as a JSON
+// string in the form produced by MarshalText without intermediate
+// allocations.
+func (uuid UUID) MarshalJSONTo(enc *jsontext.Encoder) error {
+	buf := enc.AvailableBuffer()
+	buf = append(buf, '"')
+	buf = uuid.appendText(buf)
+	buf = append(buf, '"')
+
+	return enc.WriteValue(buf)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom. It accepts a
+// JSON string in any of the forms accepted by UnmarshalText, and
+// leaves uuid unchanged on JSON null.
+func (uuid *UUID) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	value, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+
+	switch value.Kind() {
+	case 'n':
+		return nil
+
+	case '"':
+		s := value[1 : len(value)-1]
+		if bytes.IndexByte(s, '\\') >= 0 {
+			if s, err = jsontext.AppendUnquote(nil, value); err != nil {
+				return err
+			}
+		}
+
+		return uuid.UnmarshalText(s)
+
+	default:
+		return ErrInvalidFormat
+	}
+}
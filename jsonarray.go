@@ -0,0 +1,119 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type (
+	// JSONArrayDecoder reads UUIDs from a JSON array of strings one
+	// element at a time, so that arbitrarily large arrays can be
+	// processed without loading them in memory.
+	JSONArrayDecoder struct {
+		dec     *json.Decoder
+		started bool
+		done    bool
+	}
+)
+
+// NewJSONArrayDecoder returns a JSONArrayDecoder reading from r.
+func NewJSONArrayDecoder(r io.Reader) *JSONArrayDecoder {
+	return &JSONArrayDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next UUID of the array. Elements may use any of the
+// forms accepted by UUID.UnmarshalText. It returns io.EOF once the
+// closing bracket of the array has been read.
+func (d *JSONArrayDecoder) Next() (UUID, error) {
+	if d.done {
+		return Nil, io.EOF
+	}
+
+	if !d.started {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return Nil, err
+		}
+
+		if tok != json.Delim('[') {
+			return Nil, ErrInvalidFormat
+		}
+
+		d.started = true
+	}
+
+	tok, err := d.dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return Nil, err
+	}
+
+	switch tok := tok.(type) {
+	case string:
+		return parseText([]byte(tok))
+
+	case json.Delim:
+		if tok == ']' {
+			d.done = true
+			return Nil, io.EOF
+		}
+	}
+
+	return Nil, ErrInvalidFormat
+}
+
+// Read decodes up to len(batch) UUIDs into batch and returns the
+// number of UUIDs decoded. It returns io.EOF, along with any UUIDs
+// decoded in the call, once the end of the array is reached.
+func (d *JSONArrayDecoder) Read(batch UUIDs) (int, error) {
+	for i := range batch {
+		uuid, err := d.Next()
+		if err != nil {
+			return i, err
+		}
+
+		batch[i] = uuid
+	}
+
+	return len(batch), nil
+}
+
+// DecodeJSONArray calls fn for each UUID of the JSON array read from
+// r, stopping at the first error returned by fn.
+func DecodeJSONArray(r io.Reader, fn func(UUID) error) error {
+	d := NewJSONArrayDecoder(r)
+
+	for {
+		uuid, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(uuid); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/hex"
+	"log/slog"
+	"sync/atomic"
+)
+
+var (
+	redactLogs atomic.Bool
+)
+
+// SetLogRedaction controls whether UUID.LogValue returns the redacted
+// form of UUIDs, and returns the previous setting. It is useful when
+// identifiers double as capability tokens and must not be fully
+// written to log aggregation systems.
+func SetLogRedaction(enabled bool) bool {
+	return redactLogs.Swap(enabled)
+}
+
+// Redacted returns the first 8 hexadecimal digits of uuid followed by
+// an ellipsis, e.g. "f81d4fae…".
+func (uuid UUID) Redacted() string {
+	return string(uuid.appendRedacted(make([]byte, 0, 8+len("…"))))
+}
+
+// LogValue implements slog.LogValuer. It returns the redacted form of
+// uuid if enabled with SetLogRedaction, and its textual form
+// otherwise.
+func (uuid UUID) LogValue() slog.Value {
+	if redactLogs.Load() {
+		return slog.StringValue(uuid.Redacted())
+	}
+
+	return slog.StringValue(uuid.String())
+}
+
+// AppendLog appends the form of uuid returned by LogValue to buf, so
+// that logging adapters can write it without allocating a string.
+func (uuid UUID) AppendLog(buf []byte) []byte {
+	if redactLogs.Load() {
+		return uuid.appendRedacted(buf)
+	}
+
+	return uuid.appendText(buf)
+}
+
+func (uuid UUID) appendRedacted(buf []byte) []byte {
+	buf = hex.AppendEncode(buf, uuid[:4])
+
+	return append(buf, "…"...)
+}
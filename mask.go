@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+)
+
+type (
+	// Masker maps time-ordered (version 7) UUIDs to opaque-looking
+	// random (version 4) UUIDs and back with a keyed permutation, so
+	// that sortable UUIDs can be used internally without exposing
+	// their creation times externally.
+	//
+	// The 122 bits of a UUID which are neither version nor variant
+	// bits are encrypted with a balanced Feistel network whose round
+	// function is AES; the version and variant are then set to those
+	// of a version 4 UUID.
+	Masker struct {
+		block cipher.Block
+	}
+)
+
+const (
+	maskRounds = 10
+
+	mask61 = 1<<61 - 1
+)
+
+// NewMasker returns a Masker using key, which must be a 16, 24 or 32
+// bytes AES key.
+func NewMasker(key []byte) (*Masker, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Masker{block: block}, nil
+}
+
+// Mask returns the masked form of uuid. It returns ErrInvalidVersion
+// if uuid is not a version 7 UUID and ErrInvalidVariant if its
+// variant is not VariantRFC9562.
+func (m *Masker) Mask(uuid UUID) (UUID, error) {
+	if err := checkMaskable(uuid, V7); err != nil {
+		return Nil, err
+	}
+
+	l, r := splitPayload(uuid)
+	for i := 0; i < maskRounds; i++ {
+		l, r = r, l^m.round(i, r)
+	}
+
+	return joinPayload(l, r, V4), nil
+}
+
+// Unmask returns the version 7 UUID whose masked form is uuid. It
+// returns ErrInvalidVersion if uuid is not a version 4 UUID and
+// ErrInvalidVariant if its variant is not VariantRFC9562.
+func (m *Masker) Unmask(uuid UUID) (UUID, error) {
+	if err := checkMaskable(uuid, V4); err != nil {
+		return Nil, err
+	}
+
+	l, r := splitPayload(uuid)
+	for i := maskRounds - 1; i >= 0; i-- {
+		l, r = r^m.round(i, l), l
+	}
+
+	return joinPayload(l, r, V7), nil
+}
+
+func (m *Masker) round(i int, x uint64) uint64 {
+	var block [16]byte
+
+	block[0] = byte(i)
+	binary.BigEndian.PutUint64(block[8:], x)
+	m.block.Encrypt(block[:], block[:])
+
+	return binary.BigEndian.Uint64(block[:]) & mask61
+}
+
+func checkMaskable(uuid UUID, v Version) error {
+	if uuid.Version() != v {
+		return ErrInvalidVersion
+	}
+
+	if uuid.Variant() != VariantRFC9562 {
+		return ErrInvalidVariant
+	}
+
+	return nil
+}
+
+// splitPayload returns the 122 bits of uuid which are neither version
+// nor variant bits, as two 61-bit halves.
+func splitPayload(uuid UUID) (uint64, uint64) {
+	hi := binary.BigEndian.Uint64(uuid[:8])
+	lo := binary.BigEndian.Uint64(uuid[8:]) & (1<<62 - 1)
+
+	upper := hi>>16<<12 | hi&0x0FFF
+
+	return upper<<1 | lo>>61, lo & mask61
+}
+
+func joinPayload(l, r uint64, v Version) UUID {
+	var uuid UUID
+
+	upper := l >> 1
+	lo := (l&1)<<61 | r
+
+	binary.BigEndian.PutUint64(uuid[:8], upper>>12<<16|upper&0x0FFF)
+	binary.BigEndian.PutUint64(uuid[8:], lo)
+
+	uuid.SetVersion(v)
+	uuid.SetVariant(VariantRFC9562)
+
+	return uuid
+}
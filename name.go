@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+)
+
+const (
+	// nameBufferLen is the size of the stack buffer used to hash
+	// names without allocating.
+	nameBufferLen = 256
+)
+
+// Namespaces defined in RFC 9562 section 6.6 for name-based UUIDs.
+var (
+	NamespaceDNS  = Must(Parse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	NamespaceURL  = Must(Parse("6ba7b811-9dad-11d1-80b4-00c04fd430c8"))
+	NamespaceOID  = Must(Parse("6ba7b812-9dad-11d1-80b4-00c04fd430c8"))
+	NamespaceX500 = Must(Parse("6ba7b814-9dad-11d1-80b4-00c04fd430c8"))
+)
+
+// NewV3 returns the name-based (version 3) UUID of name in namespace,
+// computed with MD5. NewV5 should be preferred unless compatibility
+// with existing version 3 UUIDs is required.
+func NewV3(namespace UUID, name string) UUID {
+	return newNameBased(V3, namespace, name)
+}
+
+// NewV3Bytes is like NewV3 but takes name as a byte slice. Neither
+// allocates for names of up to 240 bytes.
+func NewV3Bytes(namespace UUID, name []byte) UUID {
+	return newNameBased(V3, namespace, name)
+}
+
+// NewV5 returns the name-based (version 5) UUID of name in namespace,
+// computed with SHA-1.
+func NewV5(namespace UUID, name string) UUID {
+	return newNameBased(V5, namespace, name)
+}
+
+// NewV5Bytes is like NewV5 but takes name as a byte slice. Neither
+// allocates for names of up to 240 bytes.
+func NewV5Bytes(namespace UUID, name []byte) UUID {
+	return newNameBased(V5, namespace, name)
+}
+
+func newNameBased[T string | []byte](v Version, namespace UUID, name T) UUID {
+	var (
+		uuid UUID
+		buf  [nameBufferLen]byte
+		data []byte
+	)
+
+	if len(namespace)+len(name) <= len(buf) {
+		data = append(append(buf[:0], namespace[:]...), name...)
+	} else {
+		data = append(namespace[:len(namespace):len(namespace)], name...)
+	}
+
+	if v == V3 {
+		sum := md5.Sum(data)
+		copy(uuid[:], sum[:])
+	} else {
+		sum := sha1.Sum(data)
+		copy(uuid[:], sum[:])
+	}
+
+	uuid.SetVersion(v)
+	uuid.SetVariant(VariantRFC9562)
+
+	return uuid
+}
+
+// NewNamespace derives a custom namespace from a fully qualified
+// domain name, as the version 5 UUID of name in NamespaceDNS.
+//
+// Teams establishing their own namespaces should derive them from a
+// domain they control rather than minting random ones, e.g.:
+//
+//	var NamespaceOrders = uuid.NewNamespace("orders.example.com")
+//
+// so that the namespace is stable, documented by its name and cannot
+// collide with the namespaces of other organizations.
+func NewNamespace(name string) UUID {
+	return NewV5(NamespaceDNS, name)
+}
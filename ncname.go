@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+)
+
+// The NCName encodings follow draft-taylor-uuid-ncname: the version
+// and variant nibbles are moved out of the 128 bits and encoded as
+// leading and trailing "bookend" letters (A to P), the remaining 120
+// bits being encoded in base32 or base64url. Since the first
+// character is always a letter, the result is a valid XML NCName,
+// HTML id and identifier in most programming languages.
+
+var (
+	ncname32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+	ncname64Encoding = base64.RawURLEncoding
+)
+
+// NCName32 returns the 26 characters base32 NCName encoding of uuid,
+// in lower case.
+func (uuid UUID) NCName32() string {
+	version, payload, variant := uuid.ncnameParts()
+
+	buf := make([]byte, 0, 26)
+	buf = append(buf, 'a'+version)
+	buf = append(buf, strings.ToLower(ncname32Encoding.EncodeToString(payload[:]))...)
+	buf = append(buf, 'a'+variant)
+
+	return string(buf)
+}
+
+// NCName64 returns the 22 characters base64 NCName encoding of uuid.
+func (uuid UUID) NCName64() string {
+	version, payload, variant := uuid.ncnameParts()
+
+	buf := make([]byte, 0, 22)
+	buf = append(buf, 'A'+version)
+	buf = append(buf, ncname64Encoding.EncodeToString(payload[:])...)
+	buf = append(buf, 'A'+variant)
+
+	return string(buf)
+}
+
+// ParseNCName decodes s from either the base32 or the base64 NCName
+// encoding, chosen according to its length. Only the case produced by
+// NCName32 and NCName64 is accepted, so that every UUID has a single
+// encoding.
+func ParseNCName(s string) (UUID, error) {
+	var (
+		payload [15]byte
+		first   byte
+		n       int
+		err     error
+	)
+
+	switch len(s) {
+	case 26:
+		if strings.ContainsAny(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			return Nil, ErrInvalidCharacter
+		}
+
+		first = 'a'
+		n, err = ncname32Encoding.Decode(payload[:], []byte(strings.ToUpper(s[1:25])))
+
+	case 22:
+		first = 'A'
+		n, err = ncname64Encoding.Decode(payload[:], []byte(s[1:21]))
+
+	default:
+		return Nil, ErrInvalidLength
+	}
+
+	version, vok := ncnameBookend(s[0], first)
+	variant, aok := ncnameBookend(s[len(s)-1], first)
+	if err != nil || n != len(payload) || !vok || !aok {
+		return Nil, ErrInvalidCharacter
+	}
+
+	return uuidFromNCNameParts(version, payload, variant), nil
+}
+
+func (uuid UUID) ncnameParts() (byte, [15]byte, byte) {
+	var (
+		payload [16]byte
+		ints    [4]uint32
+	)
+
+	for i := range ints {
+		ints[i] = binary.BigEndian.Uint32(uuid[i*4:])
+	}
+
+	version := byte(ints[1] >> 12 & 0xF)
+	variant := byte(ints[2] >> 28)
+
+	ints[1] = ints[1]&0xFFFF0000 | (ints[1]&0x0FFF)<<4 | (ints[2]&0x0FFFFFFF)>>24
+	ints[2] = (ints[2]&0x00FFFFFF)<<8 | ints[3]>>24
+	ints[3] = ints[3] << 8
+
+	for i := range ints {
+		binary.BigEndian.PutUint32(payload[i*4:], ints[i])
+	}
+
+	return version, [15]byte(payload[:15]), variant
+}
+
+func uuidFromNCNameParts(version byte, payload [15]byte, variant byte) UUID {
+	var (
+		uuid UUID
+		ints [4]uint32
+	)
+
+	copy(uuid[:], payload[:])
+	for i := range ints {
+		ints[i] = binary.BigEndian.Uint32(uuid[i*4:])
+	}
+
+	ints[3] = ints[2]<<24 | ints[3]>>8
+	ints[2] = uint32(variant)<<28 | (ints[1]&0xF)<<24 | ints[2]>>8
+	ints[1] = ints[1]&0xFFFF0000 | uint32(version)<<12 | (ints[1]&0xFFF0)>>4
+
+	for i := range ints {
+		binary.BigEndian.PutUint32(uuid[i*4:], ints[i])
+	}
+
+	return uuid
+}
+
+// ncnameBookend decodes the bookend letter c, first being the letter
+// encoding zero in the expected case.
+func ncnameBookend(c, first byte) (byte, bool) {
+	if c < first || c > first+15 {
+		return 0, false
+	}
+
+	return c - first, true
+}
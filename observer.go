@@ -0,0 +1,165 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Observer is notified of events occurring while generating
+	// UUIDs, for monitoring purposes. Its methods are called
+	// synchronously and concurrently, possibly while a generator
+	// lock is held, and must therefore be fast, safe for concurrent
+	// use and must not generate UUIDs.
+	Observer interface {
+		// Generated is called for every UUID generated.
+		Generated(v Version)
+
+		// EntropyFailure is called when reading the entropy
+		// source fails.
+		EntropyFailure(err error)
+
+		// ClockRegression is called when the clock is found to
+		// have gone backwards.
+		ClockRegression(last, current time.Time)
+
+		// PoolRefill is called when a Pool added n UUIDs to its
+		// buffer.
+		PoolRefill(n int)
+	}
+
+	// Counters is an Observer counting events. It implements
+	// expvar.Var, so that it can be published with expvar.Publish,
+	// and its Stats can be exported to other monitoring systems.
+	Counters struct {
+		generated        atomic.Uint64
+		entropyFailures  atomic.Uint64
+		clockRegressions atomic.Uint64
+		poolRefills      atomic.Uint64
+	}
+
+	// Stats is a snapshot of Counters.
+	Stats struct {
+		Generated        uint64 `json:"generated"`
+		EntropyFailures  uint64 `json:"entropy_failures"`
+		ClockRegressions uint64 `json:"clock_regressions"`
+		PoolRefills      uint64 `json:"pool_refills"`
+	}
+
+	nopObserver struct{}
+
+	observedReader struct {
+		r          io.Reader
+		observer   Observer
+		policy     EntropyRetryPolicy
+		concurrent bool
+
+		mu sync.Mutex
+	}
+)
+
+var (
+	_ Observer = (*Counters)(nil)
+)
+
+// Generated implements Observer.
+func (c *Counters) Generated(Version) {
+	c.generated.Add(1)
+}
+
+// EntropyFailure implements Observer.
+func (c *Counters) EntropyFailure(error) {
+	c.entropyFailures.Add(1)
+}
+
+// ClockRegression implements Observer.
+func (c *Counters) ClockRegression(time.Time, time.Time) {
+	c.clockRegressions.Add(1)
+}
+
+// PoolRefill implements Observer.
+func (c *Counters) PoolRefill(int) {
+	c.poolRefills.Add(1)
+}
+
+// Stats returns the current value of the counters.
+func (c *Counters) Stats() Stats {
+	return Stats{
+		Generated:        c.generated.Load(),
+		EntropyFailures:  c.entropyFailures.Load(),
+		ClockRegressions: c.clockRegressions.Load(),
+		PoolRefills:      c.poolRefills.Load(),
+	}
+}
+
+// String implements expvar.Var, returning the Stats of c as JSON.
+func (c *Counters) String() string {
+	buf, _ := json.Marshal(c.Stats())
+	return string(buf)
+}
+
+func (nopObserver) Generated(Version)                    {}
+func (nopObserver) EntropyFailure(error)                 {}
+func (nopObserver) ClockRegression(time.Time, time.Time) {}
+func (nopObserver) PoolRefill(int)                       {}
+
+// Read fills p entirely, retrying failed reads as defined by the
+// retry policy. Reads of sources which are not safe for concurrent
+// use are serialized, but the lock is not held while sleeping.
+// Errors are reported to the observer on every failed attempt and
+// returned as an *EntropyError once the policy gives up.
+func (r *observedReader) Read(p []byte) (int, error) {
+	var (
+		n       int
+		backoff = r.policy.Backoff
+	)
+
+	for attempt := 1; ; attempt++ {
+		m, err := r.read(p[n:])
+		n += m
+		if err == nil {
+			return n, nil
+		}
+
+		r.observer.EntropyFailure(err)
+
+		if attempt >= r.policy.Attempts {
+			return n, &EntropyError{Attempts: attempt, Err: err}
+		}
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if r.policy.MaxBackoff > 0 && backoff > r.policy.MaxBackoff {
+			backoff = r.policy.MaxBackoff
+		}
+	}
+}
+
+func (r *observedReader) read(p []byte) (int, error) {
+	if !r.concurrent {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+
+	return io.ReadFull(r.r, p)
+}
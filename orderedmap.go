@@ -0,0 +1,391 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"sort"
+)
+
+type (
+	// OrderedMap is a map keyed by UUID iterating over its entries in
+	// byte order, which is the creation time order of version 6 and
+	// 7 UUIDs. It is implemented as a B-tree, so that lookups,
+	// insertions and deletions take logarithmic time. The zero
+	// OrderedMap is empty and ready to use. An OrderedMap is not safe
+	// for concurrent use.
+	OrderedMap[V any] struct {
+		root *orderedNode[V]
+		len  int
+	}
+
+	orderedNode[V any] struct {
+		entries  []orderedEntry[V]
+		children []*orderedNode[V]
+	}
+
+	orderedEntry[V any] struct {
+		key   UUID
+		value V
+	}
+
+	removeKind int
+)
+
+const (
+	// orderedMaxEntries and orderedMinEntries bound the number of
+	// entries of every node but the root, for a B-tree of minimum
+	// degree 32.
+	orderedMaxEntries = 63
+	orderedMinEntries = 31
+)
+
+const (
+	removeKey removeKind = iota
+	removeMax
+)
+
+// Len returns the number of entries of m.
+func (m *OrderedMap[V]) Len() int {
+	return m.len
+}
+
+// Get returns the value associated with key, if any.
+func (m *OrderedMap[V]) Get(key UUID) (V, bool) {
+	for n := m.root; n != nil; {
+		i, found := n.search(key)
+		if found {
+			return n.entries[i].value, true
+		}
+
+		if n.leaf() {
+			break
+		}
+
+		n = n.children[i]
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set associates value with key, replacing the previous value if
+// any.
+func (m *OrderedMap[V]) Set(key UUID, value V) {
+	entry := orderedEntry[V]{key: key, value: value}
+
+	if m.root == nil {
+		m.root = &orderedNode[V]{entries: []orderedEntry[V]{entry}}
+		m.len = 1
+		return
+	}
+
+	if len(m.root.entries) >= orderedMaxEntries {
+		mid, right := m.root.split()
+		m.root = &orderedNode[V]{
+			entries:  []orderedEntry[V]{mid},
+			children: []*orderedNode[V]{m.root, right},
+		}
+	}
+
+	if m.root.insert(entry) {
+		m.len++
+	}
+}
+
+// Delete removes the entry of key and reports whether it was
+// present.
+func (m *OrderedMap[V]) Delete(key UUID) bool {
+	if m.root == nil {
+		return false
+	}
+
+	_, removed := m.root.remove(key, removeKey)
+
+	if len(m.root.entries) == 0 {
+		if m.root.leaf() {
+			m.root = nil
+		} else {
+			m.root = m.root.children[0]
+		}
+	}
+
+	if removed {
+		m.len--
+	}
+
+	return removed
+}
+
+// Min returns the smallest key of m and its value, if m is not empty.
+func (m *OrderedMap[V]) Min() (UUID, V, bool) {
+	var zero V
+
+	n := m.root
+	if n == nil {
+		return Nil, zero, false
+	}
+
+	for !n.leaf() {
+		n = n.children[0]
+	}
+
+	e := n.entries[0]
+	return e.key, e.value, true
+}
+
+// Max returns the largest key of m and its value, if m is not empty.
+func (m *OrderedMap[V]) Max() (UUID, V, bool) {
+	var zero V
+
+	n := m.root
+	if n == nil {
+		return Nil, zero, false
+	}
+
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+
+	e := n.entries[len(n.entries)-1]
+	return e.key, e.value, true
+}
+
+// All returns an iterator over the entries of m in ascending key
+// order. m must not be modified during the iteration.
+func (m *OrderedMap[V]) All() func(yield func(UUID, V) bool) {
+	return func(yield func(UUID, V) bool) {
+		if m.root != nil {
+			m.root.ascend(Nil, Nil, false, yield)
+		}
+	}
+}
+
+// Range returns an iterator over the entries of m whose key k
+// satisfies from <= k < to, in ascending key order. m must not be
+// modified during the iteration.
+func (m *OrderedMap[V]) Range(from, to UUID) func(yield func(UUID, V) bool) {
+	return func(yield func(UUID, V) bool) {
+		if m.root != nil {
+			m.root.ascend(from, to, true, yield)
+		}
+	}
+}
+
+func (n *orderedNode[V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// search returns the index of the first entry whose key is greater
+// than or equal to key, and whether it is equal.
+func (n *orderedNode[V]) search(key UUID) (int, bool) {
+	i := sort.Search(len(n.entries), func(i int) bool {
+		return Compare(n.entries[i].key, key) >= 0
+	})
+
+	return i, i < len(n.entries) && n.entries[i].key == key
+}
+
+// split moves the upper half of the entries and children of n to a
+// new node, and returns it along with the median entry.
+func (n *orderedNode[V]) split() (orderedEntry[V], *orderedNode[V]) {
+	i := orderedMaxEntries / 2
+	mid := n.entries[i]
+
+	right := &orderedNode[V]{
+		entries: append([]orderedEntry[V](nil), n.entries[i+1:]...),
+	}
+	clear(n.entries[i:])
+	n.entries = n.entries[:i]
+
+	if !n.leaf() {
+		right.children = append([]*orderedNode[V](nil), n.children[i+1:]...)
+		clear(n.children[i+1:])
+		n.children = n.children[:i+1]
+	}
+
+	return mid, right
+}
+
+// insert adds entry to the subtree rooted at n, which must not be
+// full, and reports whether a new key was added.
+func (n *orderedNode[V]) insert(entry orderedEntry[V]) bool {
+	i, found := n.search(entry.key)
+	if found {
+		n.entries[i].value = entry.value
+		return false
+	}
+
+	if n.leaf() {
+		n.entries = insertAt(n.entries, i, entry)
+		return true
+	}
+
+	if len(n.children[i].entries) >= orderedMaxEntries {
+		mid, right := n.children[i].split()
+		n.entries = insertAt(n.entries, i, mid)
+		n.children = insertAt(n.children, i+1, right)
+
+		switch c := Compare(entry.key, mid.key); {
+		case c == 0:
+			n.entries[i].value = entry.value
+			return false
+		case c > 0:
+			i++
+		}
+	}
+
+	return n.children[i].insert(entry)
+}
+
+// remove removes key, or the largest entry if kind is removeMax, from
+// the subtree rooted at n. Children are grown before descending into
+// them so that they keep at least orderedMinEntries entries.
+func (n *orderedNode[V]) remove(key UUID, kind removeKind) (orderedEntry[V], bool) {
+	var (
+		i     int
+		found bool
+	)
+
+	switch kind {
+	case removeMax:
+		if n.leaf() {
+			e := n.entries[len(n.entries)-1]
+			n.entries = removeAt(n.entries, len(n.entries)-1)
+			return e, true
+		}
+
+		i = len(n.entries)
+
+	default:
+		i, found = n.search(key)
+		if n.leaf() {
+			if !found {
+				return orderedEntry[V]{}, false
+			}
+
+			e := n.entries[i]
+			n.entries = removeAt(n.entries, i)
+			return e, true
+		}
+	}
+
+	if len(n.children[i].entries) <= orderedMinEntries {
+		n.growChild(i)
+		return n.remove(key, kind)
+	}
+
+	if found {
+		// Replace the entry by its predecessor, the largest
+		// entry of the left subtree.
+		e := n.entries[i]
+		n.entries[i], _ = n.children[i].remove(Nil, removeMax)
+		return e, true
+	}
+
+	return n.children[i].remove(key, kind)
+}
+
+// growChild adds an entry to the i-th child of n, either by rotating
+// one from a sibling or by merging the child with a sibling.
+func (n *orderedNode[V]) growChild(i int) {
+	switch {
+	case i > 0 && len(n.children[i-1].entries) > orderedMinEntries:
+		child, left := n.children[i], n.children[i-1]
+
+		child.entries = insertAt(child.entries, 0, n.entries[i-1])
+		n.entries[i-1] = left.entries[len(left.entries)-1]
+		left.entries = removeAt(left.entries, len(left.entries)-1)
+
+		if !left.leaf() {
+			child.children = insertAt(child.children, 0, left.children[len(left.children)-1])
+			left.children = removeAt(left.children, len(left.children)-1)
+		}
+
+	case i < len(n.entries) && len(n.children[i+1].entries) > orderedMinEntries:
+		child, right := n.children[i], n.children[i+1]
+
+		child.entries = append(child.entries, n.entries[i])
+		n.entries[i] = right.entries[0]
+		right.entries = removeAt(right.entries, 0)
+
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = removeAt(right.children, 0)
+		}
+
+	default:
+		if i >= len(n.entries) {
+			i--
+		}
+
+		child, right := n.children[i], n.children[i+1]
+
+		child.entries = append(child.entries, n.entries[i])
+		child.entries = append(child.entries, right.entries...)
+		child.children = append(child.children, right.children...)
+
+		n.entries = removeAt(n.entries, i)
+		n.children = removeAt(n.children, i+1)
+	}
+}
+
+// ascend calls yield for the entries of the subtree rooted at n whose
+// key is greater than or equal to from and, if bounded, lower than
+// to. It returns false once yield did.
+func (n *orderedNode[V]) ascend(from, to UUID, bounded bool, yield func(UUID, V) bool) bool {
+	i, _ := n.search(from)
+
+	for ; i < len(n.entries); i++ {
+		if !n.leaf() && !n.children[i].ascend(from, to, bounded, yield) {
+			return false
+		}
+
+		e := n.entries[i]
+		if bounded && Compare(e.key, to) >= 0 {
+			return false
+		}
+
+		if !yield(e.key, e.value) {
+			return false
+		}
+	}
+
+	if !n.leaf() {
+		return n.children[i].ascend(from, to, bounded, yield)
+	}
+
+	return true
+}
+
+func insertAt[T any](s []T, i int, v T) []T {
+	var zero T
+
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+
+	return s
+}
+
+func removeAt[T any](s []T, i int) []T {
+	var zero T
+
+	copy(s[i:], s[i+1:])
+	s[len(s)-1] = zero
+
+	return s[:len(s)-1]
+}
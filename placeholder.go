@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"strconv"
+)
+
+type (
+	// PlaceholderStyle is the syntax of the query placeholders of
+	// an SQL driver.
+	PlaceholderStyle int
+)
+
+const (
+	// PlaceholderQuestion is the "?" style of MySQL and SQLite.
+	PlaceholderQuestion PlaceholderStyle = iota
+
+	// PlaceholderDollar is the "$1" style of PostgreSQL.
+	PlaceholderDollar
+
+	// PlaceholderColon is the ":1" style of Oracle.
+	PlaceholderColon
+
+	// PlaceholderAt is the "@p1" style of SQL Server.
+	PlaceholderAt
+)
+
+// InClause returns the parenthesized list of placeholders and the
+// matching query arguments of an "IN" clause selecting uuids, such
+// as "($1, $2)". Numbered placeholders start at start, so that the
+// clause can follow other arguments; start is ignored by
+// PlaceholderQuestion. Arguments are UUIDs in their canonical text
+// form. As "IN ()" is invalid, an empty slice returns "(NULL)",
+// which matches no row.
+func (uuids UUIDs) InClause(style PlaceholderStyle, start int) (string, []any) {
+	if len(uuids) == 0 {
+		return "(NULL)", nil
+	}
+
+	var (
+		buf  = make([]byte, 0, len(uuids)*8)
+		args = make([]any, len(uuids))
+	)
+
+	buf = append(buf, '(')
+	for i, uuid := range uuids {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+
+		switch style {
+		case PlaceholderQuestion:
+			buf = append(buf, '?')
+		case PlaceholderDollar:
+			buf = strconv.AppendInt(append(buf, '$'), int64(start+i), 10)
+		case PlaceholderColon:
+			buf = strconv.AppendInt(append(buf, ':'), int64(start+i), 10)
+		case PlaceholderAt:
+			buf = strconv.AppendInt(append(buf, "@p"...), int64(start+i), 10)
+		default:
+			panic("uuid: invalid placeholder style")
+		}
+
+		args[i] = string(uuid.appendCanonical(make([]byte, 0, 36)))
+	}
+	buf = append(buf, ')')
+
+	return string(buf), args
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Pool is a Generator which pre-generates random (version 4)
+	// UUIDs in a background goroutine, so that acquiring one is
+	// a channel receive. The buffer is refilled whenever it falls
+	// to half of its size; when it is empty, UUIDs are generated
+	// synchronously.
+	//
+	// Time-based UUIDs are never buffered, as their timestamp must
+	// reflect the time at which they are acquired; they are
+	// generated by the underlying generator.
+	Pool struct {
+		gen      Generator
+		observer Observer
+		ids      chan UUID
+		refill   chan struct{}
+		done     chan struct{}
+		once     sync.Once
+		wg       sync.WaitGroup
+	}
+
+	// PoolOption configures a Pool.
+	PoolOption func(*Pool)
+)
+
+const (
+	poolRetryDelay = 10 * time.Millisecond
+)
+
+var (
+	_ Generator = (*Pool)(nil)
+)
+
+// NewPool returns a Pool buffering up to size UUIDs generated by gen
+// and starts its background goroutine. Close must be called to stop
+// it.
+func NewPool(gen Generator, size int, options ...PoolOption) *Pool {
+	if size <= 0 {
+		panic("uuid: invalid pool size")
+	}
+
+	p := &Pool{
+		gen:      gen,
+		observer: nopObserver{},
+		ids:      make(chan UUID, size),
+		refill:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	p.wg.Add(1)
+	go p.fill()
+
+	return p
+}
+
+// WithPoolObserver sets the observer notified of buffer refills.
+func WithPoolObserver(o Observer) PoolOption {
+	return func(p *Pool) {
+		p.observer = o
+	}
+}
+
+// NewV2 returns a DCE Security (version 2) UUID from the underlying
+// generator.
+func (p *Pool) NewV2(domain Domain, id uint32) (UUID, error) {
+	return p.gen.NewV2(domain, id)
+}
+
+// NewV4 returns a pre-generated random (version 4) UUID, or generates
+// one if the buffer is empty.
+func (p *Pool) NewV4() (UUID, error) {
+	var (
+		uuid UUID
+		ok   bool
+	)
+
+	select {
+	case uuid, ok = <-p.ids:
+	default:
+	}
+
+	if len(p.ids) <= cap(p.ids)/2 {
+		select {
+		case p.refill <- struct{}{}:
+		default:
+		}
+	}
+
+	if ok {
+		return uuid, nil
+	}
+
+	return p.gen.NewV4()
+}
+
+// NewV1 returns a Gregorian time-based (version 1) UUID from the
+// underlying generator.
+func (p *Pool) NewV1() (UUID, error) {
+	return p.gen.NewV1()
+}
+
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID
+// from the underlying generator.
+func (p *Pool) NewV6() (UUID, error) {
+	return p.gen.NewV6()
+}
+
+// NewV7 returns a time-ordered (version 7) UUID from the underlying
+// generator.
+func (p *Pool) NewV7() (UUID, error) {
+	return p.gen.NewV7()
+}
+
+// Close stops the background goroutine and waits for it to exit. The
+// pool remains usable after Close, generating UUIDs synchronously once
+// the buffer is drained.
+func (p *Pool) Close() {
+	p.once.Do(func() {
+		close(p.done)
+		p.wg.Wait()
+		close(p.ids)
+	})
+}
+
+func (p *Pool) fill() {
+	defer p.wg.Done()
+
+	for {
+		var n int
+
+		for len(p.ids) < cap(p.ids) {
+			uuid, err := p.gen.NewV4()
+			if err != nil {
+				// Errors are reported by the synchronous path
+				// once the buffer is drained; back off in the
+				// meantime.
+				select {
+				case <-p.done:
+					return
+				case <-time.After(poolRetryDelay):
+					continue
+				}
+			}
+
+			select {
+			case <-p.done:
+				return
+			case p.ids <- uuid:
+				n++
+			}
+		}
+
+		if n > 0 {
+			p.observer.PoolRefill(n)
+		}
+
+		select {
+		case <-p.done:
+			return
+		case <-p.refill:
+		}
+	}
+}
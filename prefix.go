@@ -0,0 +1,74 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"io"
+)
+
+const (
+	// MaxPrefixLen is the maximum length of the prefix of a UUID
+	// returned by NewPrefixed, the following byte holding the
+	// version.
+	MaxPrefixLen = 6
+)
+
+// NewPrefixed returns a custom (version 8) UUID starting with prefix,
+// the remaining bits being random. Prefixes derived from a tenant
+// identifier make all the UUIDs of a tenant adjacent in ordered
+// storage. It panics if prefix is longer than MaxPrefixLen bytes.
+func (g *Gen) NewPrefixed(prefix []byte) (UUID, error) {
+	uuid, err := newPrefixed(g.rand, prefix)
+	if err != nil {
+		return Nil, err
+	}
+
+	uuid.SetVariant(g.variant)
+	g.observer.Generated(V8)
+
+	return uuid, nil
+}
+
+// NewPrefixed is like Gen.NewPrefixed but reads its entropy from the
+// package-level entropy source.
+func NewPrefixed(prefix []byte) (UUID, error) {
+	return newPrefixed(entropy, prefix)
+}
+
+func newPrefixed(r io.Reader, prefix []byte) (UUID, error) {
+	var uuid UUID
+
+	if len(prefix) > MaxPrefixLen {
+		panic("uuid: prefix too long")
+	}
+
+	n := copy(uuid[:], prefix)
+	if _, err := io.ReadFull(r, uuid[n:]); err != nil {
+		return Nil, err
+	}
+
+	uuid.SetVersion(V8)
+	uuid.SetVariant(VariantRFC9562)
+
+	return uuid, nil
+}
+
+// HasPrefix reports whether uuid starts with prefix.
+func (uuid UUID) HasPrefix(prefix []byte) bool {
+	return bytes.HasPrefix(uuid[:], prefix)
+}
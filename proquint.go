@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+)
+
+// Proquints are pronounceable identifiers, where each 16-bit word is
+// written as five letters alternating consonants and vowels, e.g.
+// "lusab-babad". A UUID is written as eight dash-separated proquints.
+
+const (
+	proquintConsonants = "bdfghjklmnprstvz"
+	proquintVowels     = "aiou"
+
+	proquintLen = 8*5 + 7
+)
+
+// Proquint returns the proquint encoding of uuid.
+func (uuid UUID) Proquint() string {
+	buf := make([]byte, 0, proquintLen)
+
+	for i := 0; i < 16; i += 2 {
+		if i > 0 {
+			buf = append(buf, '-')
+		}
+
+		w := binary.BigEndian.Uint16(uuid[i:])
+		buf = append(buf,
+			proquintConsonants[w>>12&0xF],
+			proquintVowels[w>>10&0x3],
+			proquintConsonants[w>>6&0xF],
+			proquintVowels[w>>4&0x3],
+			proquintConsonants[w&0xF],
+		)
+	}
+
+	return string(buf)
+}
+
+// ParseProquint decodes s from the proquint encoding. Only lower case
+// letters are accepted.
+func ParseProquint(s string) (UUID, error) {
+	var uuid UUID
+
+	if len(s) != proquintLen {
+		return Nil, ErrInvalidLength
+	}
+
+	for i := 0; i < 8; i++ {
+		quint := s[i*6 : i*6+5]
+		if i > 0 && s[i*6-1] != '-' {
+			return Nil, ErrInvalidSeparator
+		}
+
+		var w uint16
+		for j := 0; j < 5; j++ {
+			var (
+				v  int
+				ok bool
+			)
+
+			if j%2 == 0 {
+				v, ok = proquintIndex(proquintConsonants, quint[j])
+				w = w<<4 | uint16(v)
+			} else {
+				v, ok = proquintIndex(proquintVowels, quint[j])
+				w = w<<2 | uint16(v)
+			}
+
+			if !ok {
+				return Nil, ErrInvalidCharacter
+			}
+		}
+
+		binary.BigEndian.PutUint16(uuid[i*2:], w)
+	}
+
+	return uuid, nil
+}
+
+func proquintIndex(alphabet string, c byte) (int, bool) {
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] == c {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// RateLimit defines the limits enforced by a RateLimitedGen.
+	RateLimit struct {
+		// Rate is the sustained number of UUIDs allowed per
+		// second. Zero means no rate limit.
+		Rate float64
+
+		// Burst is the number of UUIDs which can be generated at
+		// once above the sustained rate. It is at least one.
+		Burst int
+
+		// Quota is the total number of UUIDs which can be
+		// generated. Zero means no quota.
+		Quota uint64
+	}
+
+	// RateLimitedGen is a Generator wrapping another one and
+	// bounding the number of UUIDs it generates, for example to
+	// protect a multi-tenant service against abusive tenants with
+	// one RateLimitedGen per tenant. The rate is enforced with a
+	// token bucket.
+	RateLimitedGen struct {
+		gen   Generator
+		limit RateLimit
+		now   func() time.Time
+
+		mu     sync.Mutex
+		tokens float64
+		last   time.Time
+		issued uint64
+	}
+
+	// RateLimitError is returned by a RateLimitedGen when its rate
+	// is exceeded.
+	RateLimitError struct {
+		// RetryAfter is the time after which a UUID can be
+		// generated again.
+		RetryAfter time.Duration
+	}
+)
+
+var (
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	_ Generator = (*RateLimitedGen)(nil)
+)
+
+// NewRateLimitedGen returns a RateLimitedGen wrapping gen and
+// enforcing limit. It panics if limit.Rate is negative.
+func NewRateLimitedGen(gen Generator, limit RateLimit) *RateLimitedGen {
+	if limit.Rate < 0 {
+		panic("uuid: negative rate limit")
+	}
+
+	limit.Burst = max(limit.Burst, 1)
+
+	return &RateLimitedGen{
+		gen:    gen,
+		limit:  limit,
+		now:    time.Now,
+		tokens: float64(limit.Burst),
+	}
+}
+
+// NewV1 returns a Gregorian time-based (version 1) UUID from the
+// wrapped generator.
+func (g *RateLimitedGen) NewV1() (UUID, error) {
+	return g.generate(g.gen.NewV1)
+}
+
+// NewV2 returns a DCE Security (version 2) UUID from the wrapped
+// generator.
+func (g *RateLimitedGen) NewV2(domain Domain, id uint32) (UUID, error) {
+	return g.generate(func() (UUID, error) { return g.gen.NewV2(domain, id) })
+}
+
+// NewV4 returns a random (version 4) UUID from the wrapped generator.
+func (g *RateLimitedGen) NewV4() (UUID, error) {
+	return g.generate(g.gen.NewV4)
+}
+
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID
+// from the wrapped generator.
+func (g *RateLimitedGen) NewV6() (UUID, error) {
+	return g.generate(g.gen.NewV6)
+}
+
+// NewV7 returns a time-ordered (version 7) UUID from the wrapped
+// generator.
+func (g *RateLimitedGen) NewV7() (UUID, error) {
+	return g.generate(g.gen.NewV7)
+}
+
+// Issued returns the number of UUIDs generated so far, which counts
+// against the quota.
+func (g *RateLimitedGen) Issued() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.issued
+}
+
+func (g *RateLimitedGen) generate(fn func() (UUID, error)) (UUID, error) {
+	if err := g.acquire(); err != nil {
+		return Nil, err
+	}
+
+	uuid, err := fn()
+	if err != nil {
+		g.release()
+		return Nil, err
+	}
+
+	return uuid, nil
+}
+
+// acquire takes a token from the bucket and counts a UUID against the
+// quota, or returns an error if either is exhausted.
+func (g *RateLimitedGen) acquire() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.limit.Quota > 0 && g.issued >= g.limit.Quota {
+		return ErrQuotaExceeded
+	}
+
+	if g.limit.Rate > 0 {
+		now := g.now()
+		if !g.last.IsZero() {
+			elapsed := now.Sub(g.last).Seconds()
+			g.tokens = min(g.tokens+max(elapsed, 0)*g.limit.Rate, float64(g.limit.Burst))
+		}
+		g.last = now
+
+		if g.tokens < 1 {
+			wait := (1 - g.tokens) / g.limit.Rate
+			return &RateLimitError{RetryAfter: time.Duration(wait * float64(time.Second))}
+		}
+
+		g.tokens--
+	}
+
+	g.issued++
+
+	return nil
+}
+
+// release gives back what acquire took when generation failed.
+func (g *RateLimitedGen) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.limit.Rate > 0 {
+		g.tokens = min(g.tokens+1, float64(g.limit.Burst))
+	}
+
+	g.issued--
+}
+
+// Error implements error.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded: retry after %s", e.RetryAfter)
+}
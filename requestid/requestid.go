@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package requestid propagates UUID request identifiers through HTTP
+// servers.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+const (
+	// Header is the HTTP header carrying the request identifier.
+	Header = "X-Request-ID"
+)
+
+// Middleware returns a handler assigning a request identifier to
+// every request before calling next. The identifier is taken from
+// the inbound X-Request-ID header when it contains a valid UUID, and
+// is a new version 7 UUID otherwise. It is stored in the request
+// context with uuid.NewContext, where FromContext retrieves it, and
+// set as the X-Request-ID header of the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.Header.Get(Header))
+		if err != nil {
+			id, err = uuid.NewV7()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(Header, id.String())
+
+		next.ServeHTTP(w, r.WithContext(uuid.NewContext(r.Context(), id)))
+	})
+}
+
+// NewContext returns a copy of ctx carrying id. It is equivalent to
+// uuid.NewContext.
+func NewContext(ctx context.Context, id uuid.UUID) context.Context {
+	return uuid.NewContext(ctx, id)
+}
+
+// FromContext returns the request identifier carried by ctx, if any.
+// It is equivalent to uuid.FromContext.
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	return uuid.FromContext(ctx)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"context"
+	"fmt"
+)
+
+// GenerateSeq returns an iterator over UUIDs of version v generated by
+// the default generator, until ctx is cancelled or the caller stops
+// iterating. It can be used with range-over-func loops. v must be one
+// of V1, V4, V6 and V7; GenerateSeq panics otherwise. Like New, the
+// iterator panics if a UUID cannot be generated.
+//
+// The iterator has the underlying type of iter.Seq[UUID] and can be
+// assigned to it. It is not declared as such because the module
+// supports Go 1.22, which lacks package iter.
+func GenerateSeq(ctx context.Context, v Version) func(yield func(UUID) bool) {
+	generate := generatorFunc(v)
+
+	return func(yield func(UUID) bool) {
+		for ctx.Err() == nil {
+			if !yield(Must(generate(DefaultGenerator()))) {
+				return
+			}
+		}
+	}
+}
+
+// SeqN is like GenerateSeq, but stops after n UUIDs.
+func SeqN(v Version, n int) func(yield func(UUID) bool) {
+	generate := generatorFunc(v)
+
+	return func(yield func(UUID) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(Must(generate(DefaultGenerator()))) {
+				return
+			}
+		}
+	}
+}
+
+func generatorFunc(v Version) func(Generator) (UUID, error) {
+	switch v {
+	case V1:
+		return Generator.NewV1
+	case V4:
+		return Generator.NewV4
+	case V6:
+		return Generator.NewV6
+	case V7:
+		return Generator.NewV7
+	}
+
+	panic(fmt.Sprintf("uuid: unsupported sequence version %d", v))
+}
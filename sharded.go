@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+type (
+	// ShardedGen is a Generator spreading generation over several
+	// Gen, each stamping its own shard identifier in rand_b, so that
+	// many goroutines can generate UUIDs without contending on a
+	// single lock. Version 7 UUIDs generated by the same shard are
+	// strictly increasing, but UUIDs of different shards are only
+	// ordered up to the millisecond.
+	//
+	// The Generator methods of a ShardedGen pick a shard at random
+	// on every call, so that consecutive UUIDs they return within
+	// the same millisecond are not ordered. A goroutine requiring
+	// ordered UUIDs keeps the Gen returned by Shard instead.
+	ShardedGen struct {
+		shards []*Gen
+		next   atomic.Uint64
+	}
+)
+
+var (
+	_ Generator = (*ShardedGen)(nil)
+)
+
+// NewShardedGen returns a ShardedGen made of n shards configured with
+// options, n being rounded up to a power of two. If n is zero, four
+// shards per processor are used. Unless there is a single shard,
+// shard identifiers are allocated with WithShard. NewShardedGen
+// panics if options include WithNode or WithStateStore, which cannot
+// be shared between shards.
+func NewShardedGen(n int, options ...GenOption) *ShardedGen {
+	if n == 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+
+	if n < 0 {
+		panic("uuid: invalid number of shards")
+	}
+
+	shardBits := bits.Len(uint(n - 1))
+	if shardBits > MaxShardBits {
+		panic("uuid: invalid number of shards")
+	}
+
+	s := &ShardedGen{shards: make([]*Gen, 1<<shardBits)}
+	for i := range s.shards {
+		opts := options
+		if shardBits > 0 {
+			opts = append(options[:len(options):len(options)], WithShard(shardBits, uint64(i)))
+		}
+
+		g := NewGen(opts...)
+		if g.nodeSet || g.store != nil {
+			panic("uuid: node and state store cannot be shared by shards")
+		}
+
+		s.shards[i] = g
+	}
+
+	return s
+}
+
+// Shard returns the shards of s in turn. UUIDs generated with the
+// returned Gen are ordered, and the goroutine using it only contends
+// with the goroutines holding the same shard; pools of workers should
+// call Shard once per worker.
+func (s *ShardedGen) Shard() *Gen {
+	return s.shards[(s.next.Add(1)-1)&uint64(len(s.shards)-1)]
+}
+
+// NewV1 returns a Gregorian time-based (version 1) UUID from a random
+// shard.
+func (s *ShardedGen) NewV1() (UUID, error) {
+	return s.shard().NewV1()
+}
+
+// NewV2 returns a DCE Security (version 2) UUID from a random shard.
+func (s *ShardedGen) NewV2(domain Domain, id uint32) (UUID, error) {
+	return s.shard().NewV2(domain, id)
+}
+
+// NewV4 returns a random (version 4) UUID from a random shard.
+func (s *ShardedGen) NewV4() (UUID, error) {
+	return s.shard().NewV4()
+}
+
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID
+// from a random shard.
+func (s *ShardedGen) NewV6() (UUID, error) {
+	return s.shard().NewV6()
+}
+
+// NewV7 returns a time-ordered (version 7) UUID from a random shard.
+func (s *ShardedGen) NewV7() (UUID, error) {
+	return s.shard().NewV7()
+}
+
+// shard returns a random shard. The runtime random number generator
+// is per-thread, so that selecting a shard does not contend either.
+func (s *ShardedGen) shard() *Gen {
+	return s.shards[rand.Uint64()&uint64(len(s.shards)-1)]
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+type (
+	// ShortEncoding encodes UUIDs as 128-bit integers written in the
+	// base of an arbitrary alphabet, most significant digit first,
+	// and left-padded with the first character of the alphabet to a
+	// fixed length. It is compatible with the Python shortuuid
+	// library (version 1.0 and later).
+	ShortEncoding struct {
+		alphabet string
+		index    [256]int16
+		length   int
+	}
+)
+
+const (
+	// DefaultShortAlphabet is the base57 alphabet used by shortuuid,
+	// which excludes the easily confused 0, 1, I, O and l.
+	DefaultShortAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// Base62Alphabet is the alphanumeric alphabet, which is safe in
+	// URLs and file names.
+	Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var (
+	ErrInvalidAlphabet = errors.New("invalid alphabet")
+
+	// ShortUUID is the ShortEncoding using DefaultShortAlphabet,
+	// producing 22 characters.
+	ShortUUID = mustNewShortEncoding(DefaultShortAlphabet)
+
+	// Base62 is the ShortEncoding using Base62Alphabet, producing 22
+	// characters.
+	Base62 = mustNewShortEncoding(Base62Alphabet)
+)
+
+// NewShortEncoding returns a ShortEncoding for alphabet. As in
+// shortuuid, the characters of the alphabet are sorted and
+// duplicates are removed. It returns ErrInvalidAlphabet if alphabet
+// contains non-ASCII characters or less than two distinct characters.
+func NewShortEncoding(alphabet string) (*ShortEncoding, error) {
+	var seen [128]bool
+
+	chars := make([]byte, 0, len(alphabet))
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c >= 128 {
+			return nil, ErrInvalidAlphabet
+		}
+
+		if !seen[c] {
+			seen[c] = true
+			chars = append(chars, c)
+		}
+	}
+
+	if len(chars) < 2 {
+		return nil, ErrInvalidAlphabet
+	}
+
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+
+	e := &ShortEncoding{alphabet: string(chars)}
+	for i := range e.index {
+		e.index[i] = -1
+	}
+	for i, c := range chars {
+		e.index[c] = int16(i)
+	}
+
+	// The length is the number of digits of the largest 128-bit
+	// value, i.e. the smallest n such that base^n >= 2^128.
+	var (
+		base     = uint64(len(chars))
+		hi, lo   = uint64(0), uint64(1)
+		overflow bool
+	)
+	for !overflow {
+		hi, lo, overflow = mulAdd128(hi, lo, base, 0)
+		e.length++
+	}
+
+	return e, nil
+}
+
+func mustNewShortEncoding(alphabet string) *ShortEncoding {
+	e, err := NewShortEncoding(alphabet)
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// Alphabet returns the sorted alphabet of e.
+func (e *ShortEncoding) Alphabet() string {
+	return e.alphabet
+}
+
+// EncodedLen returns the length of the encoding of a UUID.
+func (e *ShortEncoding) EncodedLen() int {
+	return e.length
+}
+
+// Encode returns the encoding of uuid.
+func (e *ShortEncoding) Encode(uuid UUID) string {
+	var (
+		buf    = make([]byte, e.length)
+		base   = uint64(len(e.alphabet))
+		hi, lo = binary.BigEndian.Uint64(uuid[:8]), binary.BigEndian.Uint64(uuid[8:])
+		digit  uint64
+	)
+
+	for i := len(buf) - 1; i >= 0; i-- {
+		hi, lo, digit = divMod128(hi, lo, base)
+		buf[i] = e.alphabet[digit]
+	}
+
+	return string(buf)
+}
+
+// Decode decodes s. It returns ErrInvalidLength if s does not have
+// the exact encoded length, ErrInvalidCharacter if it contains
+// characters outside of the alphabet and ErrInvalidFormat if it
+// represents a value which does not fit in 128 bits.
+func (e *ShortEncoding) Decode(s string) (UUID, error) {
+	var (
+		uuid   UUID
+		base   = uint64(len(e.alphabet))
+		hi, lo uint64
+	)
+
+	if len(s) != e.length {
+		return Nil, ErrInvalidLength
+	}
+
+	for i := 0; i < len(s); i++ {
+		digit := e.index[s[i]]
+		if digit < 0 {
+			return Nil, ErrInvalidCharacter
+		}
+
+		var overflow bool
+		hi, lo, overflow = mulAdd128(hi, lo, base, uint64(digit))
+		if overflow {
+			return Nil, ErrInvalidFormat
+		}
+	}
+
+	binary.BigEndian.PutUint64(uuid[:8], hi)
+	binary.BigEndian.PutUint64(uuid[8:], lo)
+
+	return uuid, nil
+}
+
+// Short returns the encoding of uuid with ShortUUID.
+func (uuid UUID) Short() string {
+	return ShortUUID.Encode(uuid)
+}
+
+// ParseShort decodes s with ShortUUID.
+func ParseShort(s string) (UUID, error) {
+	return ShortUUID.Decode(s)
+}
+
+// Base62 returns the encoding of uuid with Base62.
+func (uuid UUID) Base62() string {
+	return Base62.Encode(uuid)
+}
+
+// ParseBase62 decodes s with Base62. Values which do not fit in 128
+// bits, such as "zzzzzzzzzzzzzzzzzzzzzz", are rejected.
+func ParseBase62(s string) (UUID, error) {
+	return Base62.Decode(s)
+}
+
+// mulAdd128 returns the 128-bit value hi:lo * m + a, and whether the
+// result overflowed.
+func mulAdd128(hi, lo, m, a uint64) (uint64, uint64, bool) {
+	carry, lo := bits.Mul64(lo, m)
+	lo, c := bits.Add64(lo, a, 0)
+
+	overflow, hi := bits.Mul64(hi, m)
+	hi, c = bits.Add64(hi, carry, c)
+
+	return hi, lo, overflow != 0 || c != 0
+}
+
+// divMod128 returns the quotient of the 128-bit value hi:lo divided by
+// d, and the remainder.
+func divMod128(hi, lo, d uint64) (uint64, uint64, uint64) {
+	qhi, r := hi/d, hi%d
+	qlo, r := bits.Div64(r, lo, d)
+
+	return qhi, qlo, r
+}
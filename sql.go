@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"fmt"
+	"sync/atomic"
+)
+
+type (
+	// ScanMode controls the representations accepted by UUID.Scan.
+	ScanMode int32
+
+	// StrictUUID is a UUID scanned with ScanStrict regardless of
+	// the mode set with SetScanMode, for columns which must hold
+	// well-formed UUIDs.
+	StrictUUID UUID
+
+	// LenientUUID is a UUID scanned with ScanLenient regardless of
+	// the mode set with SetScanMode, for columns written by foreign
+	// systems.
+	LenientUUID UUID
+)
+
+const (
+	// ScanLenient accepts, in addition to the forms of ScanStrict,
+	// the "N", "B" and "P" layouts of UUID.Format, such as the
+	// undashed hexadecimal form of Oracle RAW(16) columns converted
+	// to text, surrounding whitespace left by CHAR columns, and
+	// "urn:uuid:" prefixed UUIDs. It is the default.
+	ScanLenient ScanMode = iota
+
+	// ScanStrict only accepts the canonical text form and the 16
+	// bytes binary form.
+	ScanStrict
+)
+
+var (
+	scanMode atomic.Int32
+)
+
+// SetScanMode changes the representations accepted by UUID.Scan for
+// the whole program, and returns the previous mode. StrictUUID and
+// LenientUUID select a mode for a single value.
+func SetScanMode(m ScanMode) ScanMode {
+	if m != ScanLenient && m != ScanStrict {
+		panic(fmt.Sprintf("uuid: invalid scan mode %d", m))
+	}
+
+	return ScanMode(scanMode.Swap(int32(m)))
+}
+
+// Scan implements sql.Scanner. It accepts strings and byte slices
+// holding either the text form of a UUID or, if 16 bytes long, its
+// binary form, as returned by BINARY(16) and RAW(16) columns. Text
+// forms are accepted within the limits of the configured ScanMode.
+// NULL is rejected; sql.Null[UUID] should be used for nullable
+// columns.
+func (uuid *UUID) Scan(src any) error {
+	return uuid.scan(src, ScanMode(scanMode.Load()))
+}
+
+// Scan implements sql.Scanner like UUID.Scan with ScanStrict.
+func (uuid *StrictUUID) Scan(src any) error {
+	return (*UUID)(uuid).scan(src, ScanStrict)
+}
+
+// Scan implements sql.Scanner like UUID.Scan with ScanLenient.
+func (uuid *LenientUUID) Scan(src any) error {
+	return (*UUID)(uuid).scan(src, ScanLenient)
+}
+
+func (uuid *UUID) scan(src any, mode ScanMode) error {
+	var b []byte
+
+	switch src := src.(type) {
+	case string:
+		b = []byte(src)
+	case []byte:
+		b = src
+	default:
+		return fmt.Errorf("cannot scan %T into uuid", src)
+	}
+
+	u, err := parseScanned(b, mode)
+	if err != nil {
+		return err
+	}
+
+	*uuid = u
+	return nil
+}
+
+// parseScanned decodes b as the binary form if it is 16 bytes long,
+// before any trimming, and as a text form otherwise.
+func parseScanned(b []byte, mode ScanMode) (UUID, error) {
+	if len(b) == 16 {
+		return FromBytes(b)
+	}
+
+	if mode == ScanStrict {
+		return ParseBytes(b)
+	}
+
+	// Trimmed text must not be taken for the binary form.
+	b = bytes.TrimSpace(b)
+	if len(b) == 16 {
+		return Nil, ErrInvalidLength
+	}
+
+	return ParseAny(b)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type (
+	// GenState is the state of a Gen persisted by a StateStore.
+	GenState struct {
+		// Node and ClockSeq are the node identifier and clock
+		// sequence of version 1, 2 and 6 UUIDs.
+		Node     [6]byte
+		ClockSeq uint16
+
+		// V7Timestamp is a millisecond timestamp greater than
+		// that of every version 7 UUID generated so far.
+		V7Timestamp uint64
+	}
+
+	// StateStore persists the state of a Gen, so that UUIDs remain
+	// unique and ordered across restarts and clock resets.
+	StateStore interface {
+		// Load returns the last saved state, and false if there
+		// is none.
+		Load() (GenState, bool, error)
+
+		// Save replaces the saved state.
+		Save(GenState) error
+	}
+
+	// FileStateStore is a StateStore keeping the state in a file.
+	FileStateStore struct {
+		path string
+	}
+)
+
+const (
+	// stateReserve is how far ahead of the last version 7
+	// timestamp the saved one is, in milliseconds, so that the
+	// state is saved at most once per reserve period.
+	stateReserve = 1000
+
+	fileStateLen = 16
+)
+
+var (
+	ErrInvalidState = errors.New("invalid generator state")
+
+	_ StateStore = (*FileStateStore)(nil)
+)
+
+// WithStateStore makes the generator load its state from s before
+// generating its first UUID, and save it whenever needed. After a
+// restart, the clock sequence is incremented and version 7 UUIDs
+// sort after those generated before, their timestamp being up to one
+// second ahead of the clock for at most one second. Generation fails
+// if the state cannot be loaded or saved.
+func WithStateStore(s StateStore) GenOption {
+	return func(g *Gen) {
+		g.store = s
+	}
+}
+
+// NewFileStateStore returns a FileStateStore using the file at path.
+func NewFileStateStore(path string) *FileStateStore {
+	return &FileStateStore{path: path}
+}
+
+// Load implements StateStore.
+func (s *FileStateStore) Load() (GenState, bool, error) {
+	var state GenState
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, false, nil
+	}
+
+	if err != nil {
+		return state, false, err
+	}
+
+	if len(data) != fileStateLen {
+		return state, false, ErrInvalidState
+	}
+
+	copy(state.Node[:], data[0:6])
+	state.ClockSeq = binary.BigEndian.Uint16(data[6:8])
+	state.V7Timestamp = binary.BigEndian.Uint64(data[8:16])
+
+	return state, true, nil
+}
+
+// Save implements StateStore. The file is replaced atomically.
+func (s *FileStateStore) Save(state GenState) error {
+	data := make([]byte, 0, fileStateLen)
+	data = append(data, state.Node[:]...)
+	data = binary.BigEndian.AppendUint16(data, state.ClockSeq)
+	data = binary.BigEndian.AppendUint64(data, state.V7Timestamp)
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path)
+}
+
+// loadState restores the state saved in the store of g, the first
+// time it is called. It must be called with g.mu held.
+func (g *Gen) loadState() error {
+	if g.store == nil || g.stateLoaded {
+		return nil
+	}
+
+	state, ok, err := g.store.Load()
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		// A zero node means that no version 1, 2 or 6 UUID was
+		// generated.
+		if state.Node != ([6]byte{}) && (!g.nodeSet || g.node == state.Node) {
+			g.node = state.Node
+			g.nodeSet = true
+			g.clockSeq = (state.ClockSeq + 1) & 0x3FFF
+			g.gregorianInit = true
+		}
+
+		g.lastTimestamp = state.V7Timestamp
+		g.counter = 0
+	}
+
+	g.saved = state
+	g.stateLoaded = true
+
+	return nil
+}
+
+// saveState saves the state of g if it changed in a way which would
+// break uniqueness or ordering after a restart. It must be called
+// with g.mu held.
+func (g *Gen) saveState() error {
+	if g.store == nil {
+		return nil
+	}
+
+	state := g.saved
+	if g.gregorianInit {
+		state.Node = g.node
+		state.ClockSeq = g.clockSeq
+	}
+
+	if g.lastTimestamp > 0 && g.lastTimestamp >= state.V7Timestamp {
+		state.V7Timestamp = g.lastTimestamp + stateReserve
+	}
+
+	if state == g.saved {
+		return nil
+	}
+
+	if err := g.store.Save(state); err != nil {
+		return err
+	}
+
+	g.saved = state
+	return nil
+}
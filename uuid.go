@@ -17,18 +17,24 @@
 package uuid
 
 import (
-	"crypto/rand"
+	"bytes"
+	"crypto/subtle"
 	"encoding/binary"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"math/bits"
+	"strings"
 	"time"
 )
 
 type (
 	Version byte
 
+	// Variant is the layout of a UUID, as defined in RFC 9562
+	// section 4.1.
+	Variant byte
+
 	UUID [16]byte
 
 	UUIDs []UUID
@@ -37,7 +43,62 @@ type (
 var (
 	Nil UUID
 
+	// Max is the UUID with all bits set, as defined in RFC 9562
+	// section 5.10.
+	Max = UUID{
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+	}
+
 	ErrInvalidFormat = errors.New("invalid format")
+
+	// The following errors wrap ErrInvalidFormat and describe more
+	// precisely why an input was rejected.
+	ErrInvalidLength    = fmt.Errorf("%w: invalid length", ErrInvalidFormat)
+	ErrInvalidCharacter = fmt.Errorf("%w: invalid character", ErrInvalidFormat)
+	ErrInvalidSeparator = fmt.Errorf("%w: invalid separator", ErrInvalidFormat)
+	ErrInvalidVersion   = fmt.Errorf("%w: invalid version", ErrInvalidFormat)
+	ErrInvalidVariant   = fmt.Errorf("%w: invalid variant", ErrInvalidFormat)
+
+	ErrNoTimestamp = errors.New("no timestamp")
+
+	// canonicalOffsets are the offsets of the hexadecimal digit
+	// pairs of each byte in the canonical textual form.
+	canonicalOffsets = [16]int{
+		0, 2, 4, 6,
+		9, 11,
+		14, 16,
+		19, 21,
+		24, 26, 28, 30, 32, 34,
+	}
+
+	// hexPairs maps each byte to its two lower case hexadecimal
+	// digits, the first one in the high byte.
+	hexPairs = func() (t [256]uint16) {
+		const digits = "0123456789abcdef"
+		for i := range t {
+			t[i] = uint16(digits[i>>4])<<8 | uint16(digits[i&0xF])
+		}
+		return
+	}()
+
+	// hexDigitValues maps each hexadecimal digit to its value, and
+	// every other character to 0xFF.
+	hexDigitValues = func() (t [256]byte) {
+		for i := range t {
+			switch c := byte(i); {
+			case c >= '0' && c <= '9':
+				t[i] = c - '0'
+			case c >= 'a' && c <= 'f':
+				t[i] = c - 'a' + 10
+			case c >= 'A' && c <= 'F':
+				t[i] = c - 'A' + 10
+			default:
+				t[i] = 0xFF
+			}
+		}
+		return
+	}()
 )
 
 // String implements fmt.Stringer.
@@ -45,6 +106,72 @@ func (v Version) String() string {
 	return fmt.Sprintf("%d", v)
 }
 
+// IsValid reports whether v is one of the versions defined by RFC
+// 9562, V1 to V8.
+func (v Version) IsValid() bool {
+	return v >= V1 && v <= V8
+}
+
+const (
+	// V1 is the Gregorian time-based version.
+	V1 Version = iota + 1
+
+	// V2 is the DCE Security version, with embedded POSIX UIDs.
+	V2
+
+	// V3 is the name-based version using MD5 hashing.
+	V3
+
+	// V4 is the randomly generated version.
+	V4
+
+	// V5 is the name-based version using SHA-1 hashing.
+	V5
+
+	// V6 is the reordered Gregorian time-based version.
+	V6
+
+	// V7 is the Unix Epoch time-based version.
+	V7
+
+	// V8 is reserved for custom, experimental or vendor-specific
+	// use cases.
+	V8
+)
+
+const (
+	// VariantNCS is the reserved, NCS backward compatibility
+	// variant (0b0xxx).
+	VariantNCS Variant = iota
+
+	// VariantRFC9562 is the variant of UUIDs defined by RFC 9562
+	// (and RFC 4122) (0b10xx).
+	VariantRFC9562
+
+	// VariantMicrosoft is the reserved, Microsoft Corporation
+	// backward compatibility variant (0b110x).
+	VariantMicrosoft
+
+	// VariantFuture is reserved for future definition (0b111x).
+	VariantFuture
+)
+
+// String implements fmt.Stringer.
+func (v Variant) String() string {
+	switch v {
+	case VariantNCS:
+		return "NCS"
+	case VariantRFC9562:
+		return "RFC 9562"
+	case VariantMicrosoft:
+		return "Microsoft"
+	case VariantFuture:
+		return "Future"
+	default:
+		return fmt.Sprintf("Variant(%d)", byte(v))
+	}
+}
+
 // String implements fmt.Stringer.
 func (uuids UUIDs) String() []string {
 	var elements = make([]string, len(uuids))
@@ -56,37 +183,75 @@ func (uuids UUIDs) String() []string {
 	return elements
 }
 
-func NewV4() (UUID, error) {
-	var uuid UUID
-
-	_, err := io.ReadFull(rand.Reader, uuid[:])
+// Must returns uuid if err is nil and panics otherwise. It is
+// intended to wrap constructors which cannot fail in practice.
+func Must(uuid UUID, err error) UUID {
 	if err != nil {
-		return Nil, err
+		panic(err)
 	}
 
-	uuid[6] = uuid[6]&0x0F | 0x40
-	uuid[8] = uuid[8]&0x3F | 0x80
+	return uuid
+}
 
-	return uuid, nil
+// New returns a random (version 4) UUID. It is similar to NewV4 but
+// panics if the entropy source fails, which crypto/rand never does on
+// supported platforms.
+func New() UUID {
+	return Must(NewV4())
 }
 
-func NewV7() (UUID, error) {
-	var uuid UUID
+// MustNewV7 returns a time-ordered (version 7) UUID. It is similar to
+// NewV7 but panics if the entropy source fails.
+func MustNewV7() UUID {
+	return Must(NewV7())
+}
+
+// NewV1 returns a Gregorian time-based (version 1) UUID from the
+// default generator.
+func NewV1() (UUID, error) {
+	return DefaultGenerator().NewV1()
+}
+
+// NewV2 returns a DCE Security (version 2) UUID from the default
+// generator.
+func NewV2(domain Domain, id uint32) (UUID, error) {
+	return DefaultGenerator().NewV2(domain, id)
+}
 
-	timestamp := uint64(time.Now().UnixMilli())
-	binary.BigEndian.PutUint64(uuid[:8], timestamp<<16)
+// NewV4 returns a random (version 4) UUID from the default
+// generator.
+func NewV4() (UUID, error) {
+	return DefaultGenerator().NewV4()
+}
 
-	uuid[6] = uuid[6]&0x0F | 0x70
+// NewV4From returns a random (version 4) UUID reading its entropy
+// from r, such as an HSM-backed reader, a DRBG or recorded entropy to
+// replay.
+func NewV4From(r io.Reader) (UUID, error) {
+	var uuid UUID
 
-	if _, err := rand.Read(uuid[8:]); err != nil {
+	if _, err := io.ReadFull(r, uuid[:]); err != nil {
 		return Nil, err
 	}
 
-	uuid[8] = uuid[8]&0x3F | 0x80
+	uuid.SetVersion(V4)
+	uuid.SetVariant(VariantRFC9562)
 
 	return uuid, nil
 }
 
+// NewV6 returns a reordered Gregorian time-based (version 6) UUID
+// from the default generator.
+func NewV6() (UUID, error) {
+	return DefaultGenerator().NewV6()
+}
+
+// NewV7 returns a time-ordered (version 7) UUID from the default
+// generator.
+func NewV7() (UUID, error) {
+	return DefaultGenerator().NewV7()
+}
+
 // FromBytes creates a new UUID from a byte slice. Returns an error if
 // the slice does not have a length of 16. The bytes are copied from
 // the slice.
@@ -99,52 +264,148 @@ func FromBytes(b []byte) (UUID, error) {
 }
 
 // Parse decodes s into a UUID or returns an error if it cannot be
-// parsed.
+// parsed. It does not allocate.
 func Parse(s string) (UUID, error) {
-	return ParseBytes([]byte(s))
+	return parseCanonical(s)
 }
 
 // ParseBytes is like Parse, except it parses a byte slice instead of
 // a string.
 func ParseBytes(b []byte) (UUID, error) {
-	var uuid UUID
+	return parseCanonical(b)
+}
 
-	if len(b) != 36 {
-		return Nil, ErrInvalidFormat
+// ParseStrict is like Parse, except it also returns
+// ErrInvalidVersion if the version of the UUID is not one of V1 to V8
+// and ErrInvalidVariant if its variant is not VariantRFC9562. The Nil
+// and Max UUIDs are accepted.
+func ParseStrict(s string) (UUID, error) {
+	uuid, err := parseCanonical(s)
+	if err != nil {
+		return Nil, err
 	}
 
-	if b[8] != '-' || b[13] != '-' || b[18] != '-' || b[23] != '-' {
-		return uuid, ErrInvalidFormat
+	if uuid == Nil || uuid == Max {
+		return uuid, nil
 	}
 
-	if _, err := hex.Decode(uuid[0:4], b[0:8]); err != nil {
-		return Nil, ErrInvalidFormat
+	if !uuid.Version().IsValid() {
+		return Nil, ErrInvalidVersion
 	}
 
-	if _, err := hex.Decode(uuid[4:6], b[9:13]); err != nil {
-		return Nil, ErrInvalidFormat
+	if uuid.Variant() != VariantRFC9562 {
+		return Nil, ErrInvalidVariant
 	}
 
-	if _, err := hex.Decode(uuid[6:8], b[14:18]); err != nil {
-		return Nil, ErrInvalidFormat
+	return uuid, nil
+}
+
+// ParseOrNil is like Parse, except it returns Nil if s is not a
+// valid UUID.
+func ParseOrNil(s string) UUID {
+	uuid, err := parseCanonical(s)
+	if err != nil {
+		return Nil
 	}
 
-	if _, err := hex.Decode(uuid[8:10], b[19:23]); err != nil {
-		return Nil, ErrInvalidFormat
+	return uuid
+}
+
+// ParseLenient is like UUID.UnmarshalText, except it ignores leading
+// and trailing whitespace and a pair of surrounding single or double
+// quotes, as commonly found in configuration files and CSV exports.
+func ParseLenient(s string) (UUID, error) {
+	s = strings.TrimSpace(s)
+
+	if n := len(s); n >= 2 && (s[0] == '"' || s[0] == '\'') && s[n-1] == s[0] {
+		s = strings.TrimSpace(s[1 : n-1])
 	}
 
-	if _, err := hex.Decode(uuid[10:16], b[24:36]); err != nil {
-		return Nil, ErrInvalidFormat
+	return parseText([]byte(s))
+}
+
+// parseCanonical is shared by Parse and ParseBytes so that strings
+// are parsed without being converted to a byte slice.
+func parseCanonical[T string | []byte](s T) (UUID, error) {
+	var uuid UUID
+
+	if len(s) != 36 {
+		return Nil, ErrInvalidLength
+	}
+
+	if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return Nil, ErrInvalidSeparator
+	}
+
+	// Invalid digits decode to 0xFF, so that a single check after
+	// the loop keeps it free of branches.
+	var invalid byte
+	for i, offset := range canonicalOffsets {
+		hi := hexDigitValues[s[offset]]
+		lo := hexDigitValues[s[offset+1]]
+		invalid |= hi | lo
+
+		uuid[i] = hi<<4 | lo
+	}
+
+	if invalid&0xF0 != 0 {
+		return Nil, ErrInvalidCharacter
 	}
 
 	return uuid, nil
 }
 
+func hexValue(c byte) (byte, bool) {
+	v := hexDigitValues[c]
+	return v, v != 0xFF
+}
+
 // Version returns the version of uuid.
 func (uuid UUID) Version() Version {
 	return Version(uuid[6] >> 4)
 }
 
+// SetVersion sets the version bits of uuid to v. It panics if v does
+// not fit in 4 bits.
+func (uuid *UUID) SetVersion(v Version) {
+	if v > 0xF {
+		panic(fmt.Sprintf("uuid: invalid version %d", v))
+	}
+
+	uuid[6] = uuid[6]&0x0F | byte(v)<<4
+}
+
+// Variant returns the variant of uuid.
+func (uuid UUID) Variant() Variant {
+	switch {
+	case uuid[8]&0x80 == 0x00:
+		return VariantNCS
+	case uuid[8]&0xC0 == 0x80:
+		return VariantRFC9562
+	case uuid[8]&0xE0 == 0xC0:
+		return VariantMicrosoft
+	default:
+		return VariantFuture
+	}
+}
+
+// SetVariant sets the variant bits of uuid to v, leaving the other
+// bits of the octet untouched. It panics if v is not a known variant.
+func (uuid *UUID) SetVariant(v Variant) {
+	switch v {
+	case VariantNCS:
+		uuid[8] = uuid[8] & 0x7F
+	case VariantRFC9562:
+		uuid[8] = uuid[8]&0x3F | 0x80
+	case VariantMicrosoft:
+		uuid[8] = uuid[8]&0x1F | 0xC0
+	case VariantFuture:
+		uuid[8] = uuid[8]&0x1F | 0xE0
+	default:
+		panic(fmt.Sprintf("uuid: invalid variant %d", v))
+	}
+}
+
 // MarshalBinary implements encoding.BinaryUnmarshaler.
 func (uuid UUID) MarshalBinary() ([]byte, error) {
 	return uuid[:], nil
@@ -153,33 +414,48 @@ func (uuid UUID) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements encoding.BinaryUnmarshaler.
 func (uuid *UUID) UnmarshalBinary(data []byte) error {
 	if len(data) != 16 {
-		return ErrInvalidFormat
+		return ErrInvalidLength
 	}
 
 	copy(uuid[:], data)
 	return nil
 }
 
-// MarshalText implements encoding.TextUnmarshaler.
+// MarshalText implements encoding.TextUnmarshaler. The canonical
+// form is used unless another one was configured with SetTextFormat.
 func (uuid UUID) MarshalText() ([]byte, error) {
+	if f := textFormat.Load(); f != nil {
+		return f.append(make([]byte, 0, 38), uuid), nil
+	}
+
 	buf := make([]byte, 36)
+	uuid.encodeCanonical(buf)
+
+	return buf, nil
+}
+
+// encodeCanonical writes the canonical form of uuid to buf, which
+// must be at least 36 bytes long. Digits are looked up two at a time
+// rather than going through encoding/hex, which is measurably faster.
+func (uuid UUID) encodeCanonical(buf []byte) {
+	_ = buf[35]
+
+	for i, offset := range canonicalOffsets {
+		pair := hexPairs[uuid[i]]
+		buf[offset] = byte(pair >> 8)
+		buf[offset+1] = byte(pair)
+	}
 
-	_ = hex.Encode(buf, uuid[:4])
 	buf[8] = '-'
-	_ = hex.Encode(buf[9:13], uuid[4:6])
 	buf[13] = '-'
-	_ = hex.Encode(buf[14:18], uuid[6:8])
 	buf[18] = '-'
-	_ = hex.Encode(buf[19:23], uuid[8:10])
 	buf[23] = '-'
-	_ = hex.Encode(buf[24:], uuid[10:])
-
-	return buf, nil
 }
 
-// UnmarshalText implements encoding.TextUnmarshaler.
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts every
+// form MarshalText can produce, whatever the configured TextFormat.
 func (uuid *UUID) UnmarshalText(data []byte) error {
-	id, err := ParseBytes(data)
+	id, err := parseText(data)
 	if err != nil {
 		return err
 	}
@@ -189,19 +465,212 @@ func (uuid *UUID) UnmarshalText(data []byte) error {
 
 // String implements fmt.Stringer.
 func (uuid UUID) String() string {
-	buf, _ := uuid.MarshalText()
-	return string(buf)
+	var buf [38]byte
+	return string(uuid.appendText(buf[:0]))
+}
+
+// Set implements flag.Value. It accepts the same forms as
+// UnmarshalText.
+func (uuid *UUID) Set(s string) error {
+	return uuid.UnmarshalText([]byte(s))
+}
+
+// Type returns the name of the flag value type, as required by
+// pflag.Value.
+func (uuid *UUID) Type() string {
+	return "uuid"
+}
+
+// Bucket returns a bucket in [0, n) derived from the trailing 30 bits
+// of rand_b, which are uniformly distributed for random and
+// time-ordered UUIDs, including those generated with WithShard.
+// Buckets are computed with a multiply-shift reduction rather than a
+// modulo, which keeps the bias negligible for n much smaller than
+// 2^30. It panics if n <= 0.
+func (uuid UUID) Bucket(n int) int {
+	if n <= 0 {
+		panic("uuid: invalid bucket count")
+	}
+
+	// Only keep the bits of rand_b which cannot hold a shard ID.
+	randB := binary.BigEndian.Uint64(uuid[8:]) << (2 + MaxShardBits)
+	hi, _ := bits.Mul64(randB, uint64(n))
+
+	return int(hi)
+}
+
+// EqualConstantTime reports whether a and b are equal in a time
+// independent of their contents, for UUIDs used as bearer tokens.
+func EqualConstantTime(a, b UUID) bool {
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// Compare returns -1, 0 or 1 depending on whether a sorts before, is
+// equal to or sorts after b in byte order, which is the creation time
+// order of version 6 and 7 UUIDs.
+func Compare(a, b UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// XOR returns the bitwise exclusive or of uuid and other.
+func (uuid UUID) XOR(other UUID) UUID {
+	for i := range uuid {
+		uuid[i] ^= other[i]
+	}
+
+	return uuid
+}
+
+// Combine derives a custom (version 8) UUID from a and b by xoring
+// them and setting the version and variant bits. The result is
+// deterministic, and since xor is commutative Combine(a, b) equals
+// Combine(b, a); callers needing an ordered derivation should use a
+// name-based UUID instead.
+func Combine(a, b UUID) UUID {
+	uuid := a.XOR(b)
+
+	uuid.SetVersion(V8)
+	uuid.SetVariant(VariantRFC9562)
+
+	return uuid
+}
+
+// Next returns the UUID following uuid when both are interpreted as
+// 128-bit unsigned integers. The second value is false if uuid is Max,
+// in which case the result wraps around to Nil. Next ignores the
+// version and variant bits: the result is useful as an exclusive
+// upper bound but is not necessarily a valid UUID of any version.
+func (uuid UUID) Next() (UUID, bool) {
+	hi, lo := binary.BigEndian.Uint64(uuid[:8]), binary.BigEndian.Uint64(uuid[8:])
+
+	lo, carry := bits.Add64(lo, 1, 0)
+	hi, carry = bits.Add64(hi, 0, carry)
+
+	binary.BigEndian.PutUint64(uuid[:8], hi)
+	binary.BigEndian.PutUint64(uuid[8:], lo)
+
+	return uuid, carry == 0
 }
 
-// Timestamp returns the timestamp extracted from a UUID v7.
+// Prev returns the UUID preceding uuid when both are interpreted as
+// 128-bit unsigned integers. The second value is false if uuid is Nil,
+// in which case the result wraps around to Max.
+func (uuid UUID) Prev() (UUID, bool) {
+	hi, lo := binary.BigEndian.Uint64(uuid[:8]), binary.BigEndian.Uint64(uuid[8:])
+
+	lo, borrow := bits.Sub64(lo, 1, 0)
+	hi, borrow = bits.Sub64(hi, 0, borrow)
+
+	binary.BigEndian.PutUint64(uuid[:8], hi)
+	binary.BigEndian.PutUint64(uuid[8:], lo)
+
+	return uuid, borrow == 0
+}
+
+// RandomBits returns the n random bits of a version 4 or 7 UUID,
+// right-aligned in big-endian order: the 122 bits of a version 4 UUID
+// and the 74 bits of rand_a and rand_b of a version 7 UUID, version and
+// variant excluded. Note that the rand_a of UUIDs generated by a Gen
+// holds a randomly seeded counter. For other versions n is zero.
+func (uuid UUID) RandomBits() (b [16]byte, n int) {
+	var (
+		hi = binary.BigEndian.Uint64(uuid[:8])
+		lo = binary.BigEndian.Uint64(uuid[8:])
+
+		randA = hi & 0xfff
+		randB = lo & (1<<62 - 1)
+	)
+
+	switch uuid.Version() {
+	case V4:
+		hi, n = hi>>16<<10|randA>>2, 122
+	case V7:
+		hi, n = randA>>2, 74
+	default:
+		return b, 0
+	}
+
+	binary.BigEndian.PutUint64(b[:8], hi)
+	binary.BigEndian.PutUint64(b[8:], randA<<62|randB)
+
+	return b, n
+}
+
+// Shard returns the shard identifier stored in the bits most
+// significant bits of rand_b, as set by a Gen configured with
+// WithShard. It panics if bits is not between 1 and MaxShardBits.
+func (uuid UUID) Shard(bits int) uint64 {
+	if bits < 1 || bits > MaxShardBits {
+		panic(fmt.Sprintf("uuid: invalid shard bits %d", bits))
+	}
+
+	randB := binary.BigEndian.Uint64(uuid[8:]) & (1<<62 - 1)
+
+	return randB >> (62 - bits)
+}
+
+// Timestamp returns the timestamp extracted from a UUID v1, v6 or v7,
+// or the zero time for other versions.
 func (uuid UUID) Timestamp() time.Time {
 	var t time.Time
 
 	switch uuid.Version() {
-	case 7:
+	case V1:
+		t = gregorianTime(uuid.v1Timestamp())
+
+	case V6:
+		t = gregorianTime(uuid.v6Timestamp())
+
+	case V7:
 		timestamp := binary.BigEndian.Uint64(uuid[:8]) >> 16
 		t = time.UnixMilli(int64(timestamp))
 	}
 
 	return t
 }
+
+// TimestampFromEpoch returns the timestamp extracted from a UUID
+// generated by a Gen configured with WithEpoch(epoch).
+func (uuid UUID) TimestampFromEpoch(epoch time.Time) time.Time {
+	timestamp := binary.BigEndian.Uint64(uuid[:8]) >> 16
+
+	return time.UnixMilli(epoch.UnixMilli() + int64(timestamp))
+}
+
+// DescendingTimestamp returns the timestamp extracted from a UUID
+// generated by a Gen configured with WithDescendingOrder.
+func (uuid UUID) DescendingTimestamp() time.Time {
+	timestamp := ^binary.BigEndian.Uint64(uuid[:8]) >> 16
+
+	return time.UnixMilli(int64(timestamp))
+}
+
+// Age returns the time elapsed between the timestamp of a UUID v1, v6
+// or v7 and now. It returns ErrNoTimestamp for other versions.
+func (uuid UUID) Age(now time.Time) (time.Duration, error) {
+	if !uuid.hasTimestamp() {
+		return 0, ErrNoTimestamp
+	}
+
+	return now.Sub(uuid.Timestamp()), nil
+}
+
+// DurationBetween returns the time elapsed between the timestamps of
+// a and b, which is negative if b is older than a. It returns
+// ErrNoTimestamp if either is not a UUID v1, v6 or v7.
+func DurationBetween(a, b UUID) (time.Duration, error) {
+	if !a.hasTimestamp() || !b.hasTimestamp() {
+		return 0, ErrNoTimestamp
+	}
+
+	return b.Timestamp().Sub(a.Timestamp()), nil
+}
+
+func (uuid UUID) hasTimestamp() bool {
+	switch uuid.Version() {
+	case V1, V6, V7:
+		return true
+	}
+
+	return false
+}
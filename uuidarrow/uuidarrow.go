@@ -0,0 +1,167 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package uuidarrow maps UUIDs to Arrow FixedSizeBinary(16) arrays and
+// to Parquet FIXED_LEN_BYTE_ARRAY(16) columns annotated with the UUID
+// logical type. UUIDs are stored in their 16 bytes binary form, which
+// preserves their byte ordering.
+package uuidarrow
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	// Builder builds Arrow FixedSizeBinary(16) arrays of UUIDs.
+	Builder struct {
+		b *array.FixedSizeBinaryBuilder
+	}
+)
+
+var (
+	// DataType is the Arrow data type of UUID arrays.
+	DataType = &arrow.FixedSizeBinaryType{ByteWidth: 16}
+)
+
+// NewBuilder returns a Builder allocating from mem.
+func NewBuilder(mem memory.Allocator) *Builder {
+	return &Builder{b: array.NewFixedSizeBinaryBuilder(mem, DataType)}
+}
+
+// Append appends u to the array being built.
+func (b *Builder) Append(u uuid.UUID) {
+	b.b.Append(u[:])
+}
+
+// AppendNull appends a null value to the array being built.
+func (b *Builder) AppendNull() {
+	b.b.AppendNull()
+}
+
+// AppendValues appends uuids to the array being built. If valid is
+// not nil, uuids[i] is appended as a null value when valid[i] is
+// false.
+func (b *Builder) AppendValues(uuids uuid.UUIDs, valid []bool) {
+	b.b.Reserve(len(uuids))
+
+	for i := range uuids {
+		if valid != nil && !valid[i] {
+			b.b.AppendNull()
+			continue
+		}
+
+		b.b.Append(uuids[i][:])
+	}
+}
+
+// Len returns the number of values appended so far.
+func (b *Builder) Len() int {
+	return b.b.Len()
+}
+
+// NewArray returns the array built so far and resets the builder.
+// The caller is responsible for releasing the array.
+func (b *Builder) NewArray() *array.FixedSizeBinary {
+	return b.b.NewFixedSizeBinaryArray()
+}
+
+// Release releases the memory held by the builder.
+func (b *Builder) Release() {
+	b.b.Release()
+}
+
+// Value returns the i-th UUID of arr, or uuid.Nil if it is null. It
+// panics if arr is not a FixedSizeBinary(16) array.
+func Value(arr *array.FixedSizeBinary, i int) uuid.UUID {
+	checkArray(arr)
+
+	if arr.IsNull(i) {
+		return uuid.Nil
+	}
+
+	return uuid.UUID(arr.Value(i))
+}
+
+// Values returns the UUIDs of arr, null values being returned as
+// uuid.Nil. It panics if arr is not a FixedSizeBinary(16) array.
+func Values(arr *array.FixedSizeBinary) uuid.UUIDs {
+	checkArray(arr)
+
+	uuids := make(uuid.UUIDs, arr.Len())
+	for i := range uuids {
+		if arr.IsValid(i) {
+			uuids[i] = uuid.UUID(arr.Value(i))
+		}
+	}
+
+	return uuids
+}
+
+func checkArray(arr *array.FixedSizeBinary) {
+	if arr.DataType().(*arrow.FixedSizeBinaryType).ByteWidth != 16 {
+		panic("uuidarrow: invalid array byte width")
+	}
+}
+
+// ParquetNode returns a FIXED_LEN_BYTE_ARRAY(16) Parquet schema node
+// annotated with the UUID logical type. fieldID may be -1 if the
+// field has no identifier.
+func ParquetNode(name string, repetition parquet.Repetition, fieldID int32) (*schema.PrimitiveNode, error) {
+	return schema.NewPrimitiveNodeLogical(
+		name,
+		repetition,
+		schema.UUIDLogicalType{},
+		parquet.Types.FixedLenByteArray,
+		16,
+		fieldID,
+	)
+}
+
+// WriteParquet writes uuids to a column created from ParquetNode.
+// defLevels and repLevels are the definition and repetition levels
+// of file.FixedLenByteArrayColumnChunkWriter.WriteBatch; uuids only
+// holds the non-null values.
+func WriteParquet(w *file.FixedLenByteArrayColumnChunkWriter, uuids uuid.UUIDs, defLevels, repLevels []int16) error {
+	values := make([]parquet.FixedLenByteArray, len(uuids))
+	for i := range uuids {
+		values[i] = uuids[i][:]
+	}
+
+	_, err := w.WriteBatch(values, defLevels, repLevels)
+	return err
+}
+
+// ReadParquet reads up to len(batch) values from a column created
+// from ParquetNode, like
+// file.FixedLenByteArrayColumnChunkReader.ReadBatch. It returns the
+// number of levels read and the number of non-null UUIDs stored in
+// batch.
+func ReadParquet(r *file.FixedLenByteArrayColumnChunkReader, batch uuid.UUIDs, defLevels, repLevels []int16) (int64, int, error) {
+	values := make([]parquet.FixedLenByteArray, len(batch))
+
+	total, n, err := r.ReadBatch(int64(len(batch)), values, defLevels, repLevels)
+	for i := 0; i < n; i++ {
+		batch[i] = uuid.UUID(values[i])
+	}
+
+	return total, n, err
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package uuidcsv reads and writes UUID columns of CSV files with
+// encoding/csv. Columns are selected by index, or by name when the
+// file starts with a header record.
+package uuidcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	// Reader reads records from a csv.Reader and parses their UUID
+	// columns.
+	Reader struct {
+		r       *csv.Reader
+		columns []int
+		row     int
+	}
+
+	// Writer writes records to a csv.Writer, formatting their UUID
+	// columns.
+	Writer struct {
+		w       *csv.Writer
+		columns []int
+	}
+
+	// ColumnError locates an invalid UUID in a CSV file. Row and
+	// Column are 1-based and Row counts the header record, if any.
+	ColumnError struct {
+		Row    int
+		Column int
+		Err    error
+	}
+)
+
+var (
+	ErrMissingColumn = errors.New("missing column")
+	ErrInvalidColumn = errors.New("invalid column")
+)
+
+// NewReader returns a Reader parsing the columns of index columns, 0
+// being the first one, of every record of r. It returns an error
+// wrapping ErrInvalidColumn if an index is negative.
+func NewReader(r *csv.Reader, columns ...int) (*Reader, error) {
+	if err := checkColumns(columns); err != nil {
+		return nil, err
+	}
+
+	return &Reader{r: r, columns: columns}, nil
+}
+
+// NewHeaderReader reads the header record of r and returns a Reader
+// parsing the columns named columns. It returns an error wrapping
+// ErrMissingColumn if one of them is not in the header.
+func NewHeaderReader(r *csv.Reader, columns ...string) (*Reader, error) {
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := columnIndexes(header, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{r: r, columns: indexes, row: 1}, nil
+}
+
+// Read reads the next record and returns it along with the UUIDs of
+// its selected columns, in the order they were given. Fields are
+// parsed with uuid.ParseLenient. An invalid field is reported as a
+// *ColumnError, the record being returned nevertheless. Read returns
+// io.EOF at the end of the input.
+func (r *Reader) Read() ([]string, uuid.UUIDs, error) {
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.row++
+
+	uuids := make(uuid.UUIDs, len(r.columns))
+	for i, column := range r.columns {
+		if err := r.parse(record, column, &uuids[i]); err != nil {
+			return record, nil, err
+		}
+	}
+
+	return record, uuids, nil
+}
+
+// Validate reads every remaining record and returns the location of
+// each invalid UUID. The error is only set if reading the input
+// failed.
+func (r *Reader) Validate() ([]*ColumnError, error) {
+	var errs []*ColumnError
+
+	for {
+		record, err := r.r.Read()
+		if err == io.EOF {
+			return errs, nil
+		}
+
+		if err != nil {
+			return errs, err
+		}
+
+		r.row++
+
+		for _, column := range r.columns {
+			var u uuid.UUID
+			if err := r.parse(record, column, &u); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+}
+
+func (r *Reader) parse(record []string, column int, u *uuid.UUID) *ColumnError {
+	if column >= len(record) {
+		return &ColumnError{Row: r.row, Column: column + 1, Err: ErrMissingColumn}
+	}
+
+	id, err := uuid.ParseLenient(record[column])
+	if err != nil {
+		return &ColumnError{Row: r.row, Column: column + 1, Err: err}
+	}
+
+	*u = id
+
+	return nil
+}
+
+// NewWriter returns a Writer storing UUIDs in the columns of index
+// columns, 0 being the first one, of every record written to w. It
+// returns an error wrapping ErrInvalidColumn if an index is negative.
+func NewWriter(w *csv.Writer, columns ...int) (*Writer, error) {
+	if err := checkColumns(columns); err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w, columns: columns}, nil
+}
+
+// NewHeaderWriter writes header to w and returns a Writer storing
+// UUIDs in the columns named columns. It returns an error wrapping
+// ErrMissingColumn if one of them is not in the header.
+func NewHeaderWriter(w *csv.Writer, header []string, columns ...string) (*Writer, error) {
+	indexes, err := columnIndexes(header, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w, columns: indexes}, nil
+}
+
+// Write writes a copy of record to the underlying csv.Writer after
+// storing uuids in its selected columns, in the order they were given;
+// record itself is not modified. The copy is extended with empty
+// fields if record is too short. It panics if the number of UUIDs
+// does not match the number of columns.
+func (w *Writer) Write(record []string, uuids ...uuid.UUID) error {
+	if len(uuids) != len(w.columns) {
+		panic("uuidcsv: invalid number of UUIDs")
+	}
+
+	record = slices.Clone(record)
+	for i, column := range w.columns {
+		for len(record) <= column {
+			record = append(record, "")
+		}
+
+		record[column] = uuids[i].String()
+	}
+
+	return w.w.Write(record)
+}
+
+// Flush flushes the underlying csv.Writer and returns its error, if
+// any.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Error implements error.
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("row %d, column %d: %v", e.Row, e.Column, e.Err)
+}
+
+// Unwrap returns the error found in the column.
+func (e *ColumnError) Unwrap() error {
+	return e.Err
+}
+
+func checkColumns(columns []int) error {
+	for _, column := range columns {
+		if column < 0 {
+			return fmt.Errorf("%w %d", ErrInvalidColumn, column)
+		}
+	}
+
+	return nil
+}
+
+func columnIndexes(header, columns []string) ([]int, error) {
+	indexes := make([]int, len(columns))
+
+	for i, name := range columns {
+		indexes[i] = -1
+
+		for j, field := range header {
+			if field == name {
+				indexes[i] = j
+				break
+			}
+		}
+
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("%w %q", ErrMissingColumn, name)
+		}
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package uuidlog provides helpers logging UUIDs with go.uber.org/zap
+// and github.com/rs/zerolog. Like UUID.LogValue for log/slog, they
+// honor uuid.SetLogRedaction. UUIDs are encoded on the stack and
+// written as bytes, without intermediate string allocations.
+package uuidlog
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+	"go.gearno.de/crypto/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type (
+	zapField struct {
+		key  string
+		uuid uuid.UUID
+	}
+
+	zapArray uuid.UUIDs
+
+	zerologArray uuid.UUIDs
+
+	// logBuffer holds the longest form returned by
+	// uuid.UUID.AppendLog.
+	logBuffer [38]byte
+)
+
+var (
+	// zapBuffers holds the buffers of zap marshalers, which cannot
+	// be allocated on the stack as they are passed to the
+	// zapcore.ObjectEncoder and zapcore.ArrayEncoder interfaces.
+	zapBuffers = sync.Pool{
+		New: func() any { return new(logBuffer) },
+	}
+
+	_ zapcore.ObjectMarshaler   = zapField{}
+	_ zapcore.ArrayMarshaler    = zapArray(nil)
+	_ zerolog.LogArrayMarshaler = zerologArray(nil)
+)
+
+// Zap returns a zap field logging u as a string.
+func Zap(key string, u uuid.UUID) zap.Field {
+	return zap.Inline(zapField{key: key, uuid: u})
+}
+
+// ZapArray returns a zap field logging uuids as an array of strings.
+func ZapArray(key string, uuids uuid.UUIDs) zap.Field {
+	return zap.Array(key, zapArray(uuids))
+}
+
+// Zerolog adds u to e as a string field.
+func Zerolog(e *zerolog.Event, key string, u uuid.UUID) *zerolog.Event {
+	var buf logBuffer
+	return e.Bytes(key, u.AppendLog(buf[:0]))
+}
+
+// ZerologArray returns a zerolog.LogArrayMarshaler logging uuids as
+// an array of strings, for use with zerolog.Event.Array.
+func ZerologArray(uuids uuid.UUIDs) zerolog.LogArrayMarshaler {
+	return zerologArray(uuids)
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (f zapField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	buf := zapBuffers.Get().(*logBuffer)
+	defer zapBuffers.Put(buf)
+
+	enc.AddByteString(f.key, f.uuid.AppendLog(buf[:0]))
+
+	return nil
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (a zapArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	buf := zapBuffers.Get().(*logBuffer)
+	defer zapBuffers.Put(buf)
+
+	for _, u := range a {
+		enc.AppendByteString(u.AppendLog(buf[:0]))
+	}
+
+	return nil
+}
+
+// MarshalZerologArray implements zerolog.LogArrayMarshaler.
+func (a zerologArray) MarshalZerologArray(arr *zerolog.Array) {
+	var buf logBuffer
+
+	for _, u := range a {
+		arr.Bytes(u.AppendLog(buf[:0]))
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuidlog
+
+import (
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"go.gearno.de/crypto/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestZapAllocs(t *testing.T) {
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		zapcore.InfoLevel,
+	)
+	logger := zap.New(core)
+
+	u := uuid.New()
+	uuids := uuid.UUIDs{u, u}
+	fields := []zap.Field{Zap("id", u), ZapArray("ids", uuids)}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info("", fields...)
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations, want 0", allocs)
+	}
+}
+
+func TestZerologAllocs(t *testing.T) {
+	logger := zerolog.New(io.Discard)
+
+	u := uuid.New()
+	uuids := ZerologArray(uuid.UUIDs{u, u})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Zerolog(logger.Info(), "id", u).Array("ids", uuids).Send()
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations, want 0", allocs)
+	}
+}
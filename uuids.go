@@ -0,0 +1,316 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Between returns the sub-slice of uuids whose embedded timestamp t
+// satisfies from <= t < to. uuids must contain time-ordered UUIDs
+// sorted in ascending order, as the sub-slice is located with a
+// binary search; the result shares its backing array with uuids.
+func (uuids UUIDs) Between(from, to time.Time) UUIDs {
+	start := sort.Search(len(uuids), func(i int) bool {
+		return !uuids[i].Timestamp().Before(from)
+	})
+
+	end := start + sort.Search(len(uuids)-start, func(i int) bool {
+		return !uuids[start+i].Timestamp().Before(to)
+	})
+
+	return uuids[start:end]
+}
+
+// SortRadix sorts uuids in ascending byte order using a least
+// significant digit radix sort, which is faster than a comparison
+// sort on large slices. It allocates a buffer of the size of uuids.
+func (uuids UUIDs) SortRadix() {
+	if len(uuids) < radixSortThreshold {
+		uuids.sortCompare()
+		return
+	}
+
+	radixSort(uuids, make(UUIDs, len(uuids)), 0)
+}
+
+// SortRadixParallel is like SortRadix but uses up to workers
+// goroutines. The slice is first partitioned on its most significant
+// varying byte, then partitions are sorted concurrently.
+func (uuids UUIDs) SortRadixParallel(workers int) {
+	if workers <= 1 || len(uuids) < radixSortThreshold {
+		uuids.SortRadix()
+		return
+	}
+
+	buf := make(UUIDs, len(uuids))
+
+	for digit := 0; digit < 16; digit++ {
+		var counts [256]int
+		for i := range uuids {
+			counts[uuids[i][digit]]++
+		}
+
+		if counts[uuids[0][digit]] == len(uuids) {
+			continue
+		}
+
+		var offsets [257]int
+		for i := range counts {
+			offsets[i+1] = offsets[i] + counts[i]
+		}
+
+		next := offsets
+		for i := range uuids {
+			b := uuids[i][digit]
+			buf[next[b]] = uuids[i]
+			next[b]++
+		}
+		copy(uuids, buf)
+
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, workers)
+		)
+
+		for b := 0; b < 256; b++ {
+			start, end := offsets[b], offsets[b+1]
+			if end-start < 2 {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				radixSort(uuids[start:end], buf[start:end], digit+1)
+				<-sem
+			}()
+		}
+
+		wg.Wait()
+		return
+	}
+}
+
+const (
+	radixSortThreshold = 256
+
+	binaryChunkLen = 4096
+)
+
+// radixSort sorts uuids on the bytes from digit to 15, using buf as
+// scratch space.
+func radixSort(uuids, buf UUIDs, digit int) {
+	if len(uuids) < radixSortThreshold {
+		uuids.sortCompare()
+		return
+	}
+
+	src, dst := uuids, buf
+
+	for d := 15; d >= digit; d-- {
+		var counts [256]int
+		for i := range src {
+			counts[src[i][d]]++
+		}
+
+		// Skip digits which are identical for every UUID, such as
+		// the version or the high bytes of a timestamp.
+		if counts[src[0][d]] == len(src) {
+			continue
+		}
+
+		var offset int
+		for i, count := range counts {
+			counts[i] = offset
+			offset += count
+		}
+
+		for i := range src {
+			b := src[i][d]
+			dst[counts[b]] = src[i]
+			counts[b]++
+		}
+
+		src, dst = dst, src
+	}
+
+	if &src[0] != &uuids[0] {
+		copy(uuids, src)
+	}
+}
+
+func (uuids UUIDs) sortCompare() {
+	slices.SortFunc(uuids, Compare)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is
+// the number of UUIDs as a big-endian 64-bit integer followed by the
+// 16 bytes of each UUID.
+func (uuids UUIDs) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 8, 8+16*len(uuids))
+	binary.BigEndian.PutUint64(buf, uint64(len(uuids)))
+
+	for _, uuid := range uuids {
+		buf = append(buf, uuid[:]...)
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (uuids *UUIDs) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return ErrInvalidLength
+	}
+
+	n := binary.BigEndian.Uint64(data)
+	if (len(data)-8)%16 != 0 || uint64((len(data)-8)/16) != n {
+		return ErrInvalidLength
+	}
+
+	s := make(UUIDs, n)
+	for i := range s {
+		copy(s[i][:], data[8+16*i:])
+	}
+
+	*uuids = s
+	return nil
+}
+
+// WriteTo implements io.WriterTo, writing uuids in the format of
+// MarshalBinary.
+func (uuids UUIDs) WriteTo(w io.Writer) (int64, error) {
+	var (
+		buf     = make([]byte, 0, 16*binaryChunkLen)
+		written int64
+	)
+
+	flush := func() error {
+		n, err := w.Write(buf)
+		written += int64(n)
+		buf = buf[:0]
+		return err
+	}
+
+	buf = binary.BigEndian.AppendUint64(buf, uint64(len(uuids)))
+
+	for _, uuid := range uuids {
+		if len(buf)+16 > cap(buf) {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+
+		buf = append(buf, uuid[:]...)
+	}
+
+	return written, flush()
+}
+
+// ReadFrom implements io.ReaderFrom, reading UUIDs in the format of
+// MarshalBinary and replacing the content of uuids. Memory is
+// allocated as data is read, so that a corrupted length cannot cause
+// a large allocation.
+func (uuids *UUIDs) ReadFrom(r io.Reader) (int64, error) {
+	var (
+		buf  = make([]byte, 16*binaryChunkLen)
+		read int64
+	)
+
+	n, err := io.ReadFull(r, buf[:8])
+	read += int64(n)
+	if err != nil {
+		return read, err
+	}
+
+	count := binary.BigEndian.Uint64(buf)
+	s := make(UUIDs, 0, min(count, binaryChunkLen))
+
+	for remaining := count; remaining > 0; {
+		chunk := min(remaining, binaryChunkLen)
+
+		n, err := io.ReadFull(r, buf[:16*chunk])
+		read += int64(n)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return read, err
+		}
+
+		for i := uint64(0); i < chunk; i++ {
+			s = append(s, UUID(buf[16*i:16*i+16]))
+		}
+
+		remaining -= chunk
+	}
+
+	*uuids = s
+	return read, nil
+}
+
+// FormatMany appends the textual form of every UUID of uuids, as
+// produced by MarshalText, to buf, separated by sep, and returns the
+// extended buffer. Reusing buf across calls avoids allocations.
+func (uuids UUIDs) FormatMany(buf []byte, sep byte) []byte {
+	for i, uuid := range uuids {
+		if i > 0 {
+			buf = append(buf, sep)
+		}
+
+		buf = uuid.appendText(buf)
+	}
+
+	return buf
+}
+
+// WriteText writes the textual form of every UUID of uuids to w,
+// separated by sep, without a trailing separator. UUIDs are encoded
+// in chunks, so that large slices are written with few calls to
+// w.Write and without per-UUID allocations.
+func (uuids UUIDs) WriteText(w io.Writer, sep byte) (int64, error) {
+	var (
+		buf     = make([]byte, 0, 39*binaryChunkLen)
+		written int64
+	)
+
+	for start := 0; start < len(uuids); start += binaryChunkLen {
+		if start > 0 {
+			buf = append(buf, sep)
+		}
+
+		buf = uuids[start:min(start+binaryChunkLen, len(uuids))].FormatMany(buf, sep)
+
+		n, err := w.Write(buf)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+
+		buf = buf[:0]
+	}
+
+	return written, nil
+}
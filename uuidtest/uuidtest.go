@@ -0,0 +1,202 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+// Package uuidtest provides fake generators and assertions for tests
+// of code generating UUIDs.
+package uuidtest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.gearno.de/crypto/uuid"
+)
+
+type (
+	// SequentialGen is a Generator returning UUIDs whose last 62
+	// bits hold a counter starting at 1, with the version and
+	// variant bits set accordingly, e.g.
+	// 00000000-0000-7000-8000-000000000001 for the first version 7
+	// UUID. UUIDs of the same version are therefore ordered and
+	// easy to predict in test expectations.
+	SequentialGen struct {
+		mu sync.Mutex
+		n  uint64
+	}
+
+	// FixedGen is a Generator returning predefined UUIDs in order,
+	// whatever the version requested.
+	FixedGen struct {
+		mu    sync.Mutex
+		uuids uuid.UUIDs
+	}
+)
+
+var (
+	ErrExhausted = errors.New("no more uuids")
+
+	_ uuid.Generator = (*SequentialGen)(nil)
+	_ uuid.Generator = (*FixedGen)(nil)
+)
+
+// NewSequentialGen returns a SequentialGen.
+func NewSequentialGen() *SequentialGen {
+	return &SequentialGen{}
+}
+
+// NewV1 implements uuid.Generator.
+func (g *SequentialGen) NewV1() (uuid.UUID, error) {
+	return g.next(uuid.V1), nil
+}
+
+// NewV2 implements uuid.Generator. The domain and id are set as in a
+// real version 2 UUID.
+func (g *SequentialGen) NewV2(domain uuid.Domain, id uint32) (uuid.UUID, error) {
+	u := g.next(uuid.V2)
+	binary.BigEndian.PutUint32(u[0:4], id)
+	u[9] = byte(domain)
+
+	return u, nil
+}
+
+// NewV4 implements uuid.Generator.
+func (g *SequentialGen) NewV4() (uuid.UUID, error) {
+	return g.next(uuid.V4), nil
+}
+
+// NewV6 implements uuid.Generator.
+func (g *SequentialGen) NewV6() (uuid.UUID, error) {
+	return g.next(uuid.V6), nil
+}
+
+// NewV7 implements uuid.Generator.
+func (g *SequentialGen) NewV7() (uuid.UUID, error) {
+	return g.next(uuid.V7), nil
+}
+
+func (g *SequentialGen) next(v uuid.Version) uuid.UUID {
+	var u uuid.UUID
+
+	g.mu.Lock()
+	g.n++
+	binary.BigEndian.PutUint64(u[8:], g.n)
+	g.mu.Unlock()
+
+	u.SetVersion(v)
+	u.SetVariant(uuid.VariantRFC9562)
+
+	return u
+}
+
+// NewFixedGen returns a FixedGen returning uuids in order, then
+// ErrExhausted.
+func NewFixedGen(uuids ...uuid.UUID) *FixedGen {
+	return &FixedGen{uuids: uuids}
+}
+
+// NewV1 implements uuid.Generator.
+func (g *FixedGen) NewV1() (uuid.UUID, error) {
+	return g.next()
+}
+
+// NewV2 implements uuid.Generator.
+func (g *FixedGen) NewV2(uuid.Domain, uint32) (uuid.UUID, error) {
+	return g.next()
+}
+
+// NewV4 implements uuid.Generator.
+func (g *FixedGen) NewV4() (uuid.UUID, error) {
+	return g.next()
+}
+
+// NewV6 implements uuid.Generator.
+func (g *FixedGen) NewV6() (uuid.UUID, error) {
+	return g.next()
+}
+
+// NewV7 implements uuid.Generator.
+func (g *FixedGen) NewV7() (uuid.UUID, error) {
+	return g.next()
+}
+
+func (g *FixedGen) next() (uuid.UUID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.uuids) == 0 {
+		return uuid.Nil, ErrExhausted
+	}
+
+	u := g.uuids[0]
+	g.uuids = g.uuids[1:]
+
+	return u, nil
+}
+
+// SetDefaultGenerator replaces the default generator of package uuid
+// with g for the duration of the test t, restoring the previous one
+// when t completes. Tests using it must not run in parallel with
+// other tests generating UUIDs.
+func SetDefaultGenerator(t testing.TB, g uuid.Generator) {
+	t.Helper()
+
+	previous := uuid.SetDefaultGenerator(g)
+	t.Cleanup(func() {
+		uuid.SetDefaultGenerator(previous)
+	})
+}
+
+// AssertVersion reports an error to t if the version of u is not v.
+func AssertVersion(t testing.TB, u uuid.UUID, v uuid.Version) bool {
+	t.Helper()
+
+	if got := u.Version(); got != v {
+		t.Errorf("uuid %s: expected version %d, got %d", u, v, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertVariant reports an error to t if the variant of u is not v.
+func AssertVariant(t testing.TB, u uuid.UUID, v uuid.Variant) bool {
+	t.Helper()
+
+	if got := u.Variant(); got != v {
+		t.Errorf("uuid %s: expected variant %s, got %s", u, v, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertOrdered reports an error to t if uuids are not in strictly
+// increasing byte order.
+func AssertOrdered(t testing.TB, uuids uuid.UUIDs) bool {
+	t.Helper()
+
+	for i := 1; i < len(uuids); i++ {
+		if bytes.Compare(uuids[i-1][:], uuids[i][:]) >= 0 {
+			t.Errorf("uuids not ordered at index %d: %s >= %s", i, uuids[i-1], uuids[i])
+			return false
+		}
+	}
+
+	return true
+}
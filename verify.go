@@ -0,0 +1,227 @@
+// Copyright (c) 2024 Bryan Frimin <bryan@frimin.fr>.
+//
+// Permission to use, copy, modify, and/or distribute this software
+// for any purpose with or without fee is hereby granted, provided
+// that the above copyright notice and this permission notice appear
+// in all copies.
+//
+// THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL
+// WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED
+// WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE
+// AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR
+// CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS
+// OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT,
+// NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN
+// CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+
+package uuid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// VerifyOptions configures Verify. The zero VerifyOptions only
+	// checks conformance to RFC 9562.
+	VerifyOptions struct {
+		// Versions lists the accepted versions. Every version
+		// defined by RFC 9562 is accepted if it is empty.
+		Versions []Version
+
+		// RejectNilMax rejects the Nil and Max UUIDs, which are
+		// otherwise accepted as defined by RFC 9562.
+		RejectNilMax bool
+
+		// CustomV8 validates the custom layout of version 8
+		// UUIDs, if not nil.
+		CustomV8 func(UUID) error
+
+		// NotBefore and NotAfter reject version 1, 6 and 7 UUIDs
+		// whose timestamp is before NotBefore or after NotAfter,
+		// if not zero. MaxFuture rejects timestamps later than
+		// MaxFuture after the current time, if not zero.
+		NotBefore time.Time
+		NotAfter  time.Time
+		MaxFuture time.Duration
+	}
+
+	// ComplianceError is returned by Verify and lists every
+	// problem found.
+	ComplianceError struct {
+		UUID UUID
+		Errs []error
+	}
+
+	// TestVector is an example UUID from RFC 9562 along with the
+	// inputs it was generated from.
+	TestVector struct {
+		// Name identifies the vector, e.g. "RFC 9562 A.6".
+		Name    string
+		UUID    UUID
+		Version Version
+
+		// Time is the timestamp of time-based UUIDs, and the
+		// zero time otherwise.
+		Time time.Time
+
+		// Namespace and Data are the inputs of name-based
+		// UUIDs.
+		Namespace UUID
+		Data      string
+	}
+)
+
+var (
+	ErrUnexpectedVersion = fmt.Errorf("%w: unexpected version", ErrInvalidVersion)
+	ErrNilOrMax          = errors.New("nil or max uuid")
+	ErrTimestampBounds   = errors.New("timestamp out of bounds")
+)
+
+// Verify checks that uuid conforms to RFC 9562 and to opts, and
+// returns a *ComplianceError listing every problem found otherwise.
+// The listed errors are ErrInvalidVariant, ErrInvalidVersion,
+// ErrUnexpectedVersion, ErrNilOrMax, ErrTimestampBounds and those
+// returned by opts.CustomV8; they can be checked with errors.Is.
+func Verify(uuid UUID, opts VerifyOptions) error {
+	var errs []error
+
+	if uuid == Nil || uuid == Max {
+		if opts.RejectNilMax {
+			errs = append(errs, ErrNilOrMax)
+		}
+
+		return newComplianceError(uuid, errs)
+	}
+
+	if uuid.Variant() != VariantRFC9562 {
+		errs = append(errs, ErrInvalidVariant)
+	}
+
+	v := uuid.Version()
+	switch {
+	case !v.IsValid():
+		errs = append(errs, ErrInvalidVersion)
+
+	case len(opts.Versions) > 0 && !containsVersion(opts.Versions, v):
+		errs = append(errs, ErrUnexpectedVersion)
+
+	case v == V8 && opts.CustomV8 != nil:
+		if err := opts.CustomV8(uuid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if uuid.hasTimestamp() && !opts.timestampInBounds(uuid.Timestamp()) {
+		errs = append(errs, ErrTimestampBounds)
+	}
+
+	return newComplianceError(uuid, errs)
+}
+
+func (opts VerifyOptions) timestampInBounds(t time.Time) bool {
+	switch {
+	case !opts.NotBefore.IsZero() && t.Before(opts.NotBefore):
+		return false
+	case !opts.NotAfter.IsZero() && t.After(opts.NotAfter):
+		return false
+	case opts.MaxFuture > 0 && t.After(time.Now().Add(opts.MaxFuture)):
+		return false
+	}
+
+	return true
+}
+
+func newComplianceError(uuid UUID, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ComplianceError{UUID: uuid, Errs: errs}
+}
+
+func containsVersion(versions []Version, v Version) bool {
+	for _, version := range versions {
+		if version == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error implements error.
+func (e *ComplianceError) Error() string {
+	problems := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		problems[i] = err.Error()
+	}
+
+	return fmt.Sprintf("uuid %s is not compliant: %s", e.UUID, strings.Join(problems, ", "))
+}
+
+// Unwrap returns the problems found.
+func (e *ComplianceError) Unwrap() []error {
+	return e.Errs
+}
+
+// TestVectors returns the example UUIDs of RFC 9562 appendices A and
+// B, for use in the tests of UUID generators and parsers.
+func TestVectors() []TestVector {
+	gregorian := time.Date(2022, time.February, 22, 19, 22, 22, 0, time.UTC)
+
+	return []TestVector{
+		{
+			Name:    "RFC 9562 A.1",
+			UUID:    Must(Parse("c232ab00-9414-11ec-b3c8-9f6bdeced846")),
+			Version: V1,
+			Time:    gregorian,
+		},
+		{
+			Name:      "RFC 9562 A.2",
+			UUID:      Must(Parse("5df41881-3aed-3515-88a7-2f4a814cf09e")),
+			Version:   V3,
+			Namespace: NamespaceDNS,
+			Data:      "www.example.com",
+		},
+		{
+			Name:    "RFC 9562 A.3",
+			UUID:    Must(Parse("919108f7-52d1-4320-9bac-f847db4148a8")),
+			Version: V4,
+		},
+		{
+			Name:      "RFC 9562 A.4",
+			UUID:      Must(Parse("2ed6657d-e927-568b-95e1-2665a8aea6a2")),
+			Version:   V5,
+			Namespace: NamespaceDNS,
+			Data:      "www.example.com",
+		},
+		{
+			Name:    "RFC 9562 A.5",
+			UUID:    Must(Parse("1ec9414c-232a-6b00-b3c8-9f6bdeced846")),
+			Version: V6,
+			Time:    gregorian,
+		},
+		{
+			Name:    "RFC 9562 A.6",
+			UUID:    Must(Parse("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")),
+			Version: V7,
+			Time:    gregorian,
+		},
+		{
+			Name:    "RFC 9562 B.1",
+			UUID:    Must(Parse("2489e9ad-2ee2-8e00-8ec9-32d5f69181c0")),
+			Version: V8,
+			Time:    gregorian,
+		},
+		{
+			Name:      "RFC 9562 B.2",
+			UUID:      Must(Parse("5c146b14-3c52-8afd-938a-375d0df1fbf6")),
+			Version:   V8,
+			Namespace: NamespaceDNS,
+			Data:      "www.example.com",
+		},
+	}
+}